@@ -0,0 +1,55 @@
+package spectra
+
+import (
+	"reflect"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AssertEqual reports an error if got and want are not deeply equal.
+// The outcome is recorded as an "assertion" span event with both values.
+//
+// Example:
+//
+//	st.AssertEqual("status code", resp.StatusCode, http.StatusOK)
+func (t *T) AssertEqual(name string, got, want any) {
+	t.Helper()
+
+	passed := reflect.DeepEqual(got, want)
+
+	t.recordAssertion(name, passed,
+		attribute.String(attrAssertionGot, formatf("%v", got)),
+		attribute.String(attrAssertionWant, formatf("%v", want)),
+	)
+
+	if !passed {
+		t.Errorf("%s: got %v, want %v", name, got, want)
+	}
+}
+
+// AssertNoError reports an error if err is not nil.
+// The outcome is recorded as an "assertion" span event with the error, if any.
+func (t *T) AssertNoError(err error) {
+	t.Helper()
+
+	const name = "no error"
+
+	if err != nil {
+		t.recordAssertion(name, false, attribute.String(attrAssertionError, err.Error()))
+		t.Errorf("%s: unexpected error: %v", name, err)
+
+		return
+	}
+
+	t.recordAssertion(name, true)
+}
+
+func (t *T) recordAssertion(name string, passed bool, attrs ...attribute.KeyValue) {
+	t.Span().AddEvent(assertionEventName, trace.WithAttributes(
+		append([]attribute.KeyValue{
+			attribute.String(attrAssertionName, name),
+			attribute.Bool(attrAssertionPassed, passed),
+		}, attrs...)...,
+	))
+}
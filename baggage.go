@@ -0,0 +1,42 @@
+package spectra
+
+import "go.opentelemetry.io/otel/baggage"
+
+// SetBaggage adds members to the baggage carried by the test context.
+// Spans started afterwards, and any outgoing requests made with Context(),
+// carry the updated baggage. Existing members with the same key are replaced.
+//
+// Example:
+//
+//	func TestCheckout(t *testing.T) {
+//	    st, _ := sp.New(t)
+//	    member, _ := baggage.NewMember("test.correlation_id", "abc123")
+//	    st.SetBaggage(member)
+//	    client.Do(req.WithContext(st.Context()))
+//	}
+func (t *T) SetBaggage(members ...baggage.Member) {
+	t.Helper()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	bag := baggage.FromContext(t.ctx)
+
+	for _, member := range members {
+		updated, err := bag.SetMember(member)
+		if err != nil {
+			t.TB.Errorf("spectra: set baggage member %q: %v", member.Key(), err)
+
+			return
+		}
+
+		bag = updated
+	}
+
+	t.ctx = baggage.ContextWithBaggage(t.ctx, bag)
+}
+
+// Baggage returns the baggage carried by the test context.
+func (t *T) Baggage() baggage.Baggage {
+	return baggage.FromContext(t.Context())
+}
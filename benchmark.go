@@ -0,0 +1,151 @@
+package spectra
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// B wraps testing.B with OpenTelemetry instrumentation.
+// Each invocation of the benchmark function gets its own span, which records
+// b.N, ns/op, and allocations when the invocation completes.
+type B struct {
+	*testing.B
+
+	ctx     context.Context //nolint:containedctx // Context is needed for span propagation in benchmarks.
+	span    trace.Span
+	tracer  trace.Tracer
+	spectra *Spectra
+
+	memStart runtime.MemStats
+}
+
+// NewB creates a new instrumented benchmark wrapper.
+// The go test runner calls a benchmark function several times with growing
+// b.N, so each call produces a span for that round. Like test spans,
+// benchmark spans are parented under the active suite and carry the default
+// attributes, and are named with the configured span namer.
+//
+// Example:
+//
+//	func BenchmarkQuery(b *testing.B) {
+//	    sb, err := sp.NewB(b)
+//	    if err != nil {
+//	        b.Fatalf("spectra: %v", err)
+//	    }
+//	    for b.Loop() {
+//	        db.Query(sb.Context(), "SELECT ...")
+//	    }
+//	}
+func (s *Spectra) NewB(b *testing.B) (*B, error) {
+	b.Helper()
+
+	tracer, err := s.testTracer()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newB(s.parentContext(), tracer, b), nil
+}
+
+func (s *Spectra) newB(parent context.Context, tracer trace.Tracer, b *testing.B) *B {
+	b.Helper()
+
+	ctx, span := tracer.Start(
+		parent,
+		s.testSpanName(b.Name()),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(s.defaultAttributes()...),
+		trace.WithAttributes(
+			attribute.String(attrTestName, b.Name()),
+			attribute.Int(attrBenchmarkN, b.N),
+		),
+	)
+
+	sb := &B{
+		B:       b,
+		ctx:     ctx,
+		span:    span,
+		tracer:  tracer,
+		spectra: s,
+	}
+
+	runtime.ReadMemStats(&sb.memStart)
+
+	b.Cleanup(func() {
+		sb.recordResults()
+
+		switch {
+		case b.Failed():
+			span.SetStatus(codes.Error, "benchmark failed")
+		case b.Skipped():
+			span.SetStatus(codes.Ok, "benchmark skipped")
+		default:
+			span.SetStatus(codes.Ok, "benchmark passed")
+		}
+
+		span.End()
+	})
+
+	return sb
+}
+
+// Context returns the context associated with this benchmark's span.
+func (b *B) Context() context.Context {
+	return b.ctx
+}
+
+// Span returns the span associated with this benchmark.
+func (b *B) Span() trace.Span {
+	return b.span
+}
+
+// StartSpan creates a child span of the benchmark span.
+// The caller is responsible for ending the span with span.End().
+//
+//nolint:spancheck // Caller is responsible for ending the span.
+func (b *B) StartSpan(name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return b.tracer.Start(b.ctx, name, opts...)
+}
+
+// ReportMetric reports a custom benchmark metric and records it as a span attribute.
+func (b *B) ReportMetric(n float64, unit string) {
+	b.B.ReportMetric(n, unit)
+
+	b.span.SetAttributes(attribute.Float64(attrBenchmarkMetric+unit, n))
+}
+
+// Run runs a sub-benchmark with its own span as a child of the current benchmark span.
+func (b *B) Run(name string, f func(*B)) bool {
+	b.Helper()
+
+	return b.B.Run(name, func(innerB *testing.B) {
+		innerB.Helper()
+
+		f(b.spectra.newB(b.ctx, b.tracer, innerB))
+	})
+}
+
+// recordResults sets the iteration count, timing, and allocation attributes.
+func (b *B) recordResults() {
+	if b.N == 0 {
+		return
+	}
+
+	var memEnd runtime.MemStats
+
+	runtime.ReadMemStats(&memEnd)
+
+	n := float64(b.N)
+
+	b.span.SetAttributes(
+		attribute.Int(attrBenchmarkN, b.N),
+		attribute.Float64(attrBenchmarkNsPerOp, float64(b.Elapsed().Nanoseconds())/n),
+		attribute.Float64(attrBenchmarkAllocsPerOp, float64(memEnd.Mallocs-b.memStart.Mallocs)/n),
+		attribute.Float64(attrBenchmarkBytesPerOp, float64(memEnd.TotalAlloc-b.memStart.TotalAlloc)/n),
+	)
+}
@@ -0,0 +1,74 @@
+package spectra
+
+import (
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// logCapture redirects the standard library logger while tests run, so lines
+// logged through it become log events on the most recently started test that
+// is still running. Lines are still written to the previous output, once.
+//
+// The log package holds its output lock while calling Write, so recording a
+// line must not log through it again; a LogScrubber that does deadlocks.
+type logCapture struct {
+	mu       sync.Mutex
+	tests    []*T
+	previous io.Writer
+}
+
+// Write passes p through to the previous output and records it as a log
+// event on the current test.
+func (c *logCapture) Write(p []byte) (int, error) {
+	var current *T
+
+	c.mu.Lock()
+
+	previous := c.previous
+	if len(c.tests) > 0 {
+		current = c.tests[len(c.tests)-1]
+	}
+
+	c.mu.Unlock()
+
+	if current != nil {
+		current.recordLog(strings.TrimSuffix(string(p), "\n"), levelInfo)
+	}
+
+	if previous == nil {
+		return len(p), nil
+	}
+
+	return previous.Write(p) //nolint:wrapcheck // Passes the previous output's result through unchanged.
+}
+
+// attach starts capturing log lines for t, installing the capture as the
+// standard logger's output for the first test.
+func (c *logCapture) attach(t *T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.tests) == 0 {
+		c.previous = log.Writer()
+		log.SetOutput(c)
+	}
+
+	c.tests = append(c.tests, t)
+}
+
+// detach stops capturing log lines for t, restoring the previous output once
+// no test is left.
+func (c *logCapture) detach(t *T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tests = slices.DeleteFunc(c.tests, func(other *T) bool { return other == t })
+
+	if len(c.tests) == 0 {
+		log.SetOutput(c.previous)
+		c.previous = nil
+	}
+}
@@ -0,0 +1,157 @@
+package spectra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Config exposes the internal configuration to external tests.
+type Config = config
+
+// ResolvedConfig returns the configuration sp was initialized with,
+// after defaults and environment fallbacks have been applied.
+func ResolvedConfig(sp *Spectra) Config {
+	return sp.config
+}
+
+// NewConfig applies opts to an empty configuration without validating it.
+func NewConfig(opts ...Option) Config {
+	cfg := config{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// ParseEndpoint returns the host:port spectra exports to for endpoint.
+func ParseEndpoint(endpoint string) (string, error) {
+	_, hostPort, err := parseProtocol(endpoint)
+
+	return hostPort, err
+}
+
+// NewTracerProvider builds the tracer provider Init would create for cfg,
+// exporting to exporter instead of an OTLP collector.
+func NewTracerProvider(cfg Config, exporter sdktrace.SpanExporter) (*sdktrace.TracerProvider, error) {
+	cfg, err := validateConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTracerProvider(cfg, nil, exporter), nil
+}
+
+// NewSubtest starts a subtest of t for tb, as Run does for a *testing.T.
+func NewSubtest(t *T, tb testing.TB) *T {
+	return t.newSubtest(tb)
+}
+
+// TemporalitySelector returns the temporality selector the OTLP metric
+// exporters use for cfg.
+func TemporalitySelector(cfg Config) sdkmetric.TemporalitySelector {
+	return temporalitySelector(cfg.MetricTemporality)
+}
+
+// StartTime returns the time t started, from which its duration is measured.
+func StartTime(t *T) time.Time {
+	return t.startTime
+}
+
+// NewWithExporter initializes spectra for cfg with traces exported to
+// exporter and metrics disabled.
+func NewWithExporter(cfg Config, exporter sdktrace.SpanExporter) (*Spectra, error) {
+	cfg.DisableMetrics = true
+
+	cfg, err := validateConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := newTracerProvider(cfg, nil, exporter)
+
+	return &Spectra{
+		config:         cfg,
+		tracerProvider: tp,
+		tracer:         tp.Tracer("spectra"),
+		initialized:    true,
+	}, nil
+}
+
+// NewWithLogExporter initializes spectra for cfg with logs exported to
+// exporter. Traces go to the global tracer provider and metrics are disabled.
+func NewWithLogExporter(cfg Config, exporter sdklog.Exporter) (*Spectra, error) {
+	cfg.DisableTraces = true
+	cfg.DisableMetrics = true
+	cfg.ExportLogs = true
+
+	cfg, err := validateConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	lp := newLoggerProvider(nil, exporter)
+
+	return &Spectra{
+		config:         cfg,
+		loggerProvider: lp,
+		logger:         lp.Logger("spectra"),
+		initialized:    true,
+	}, nil
+}
+
+// NewWithMetricReader initializes spectra for cfg with metrics collected by
+// reader. Traces go to the global tracer provider.
+func NewWithMetricReader(cfg Config, reader sdkmetric.Reader) (*Spectra, error) {
+	cfg.DisableTraces = true
+
+	cfg, err := validateConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := newMeterProvider(cfg, nil, reader)
+
+	sp := &Spectra{
+		config:        cfg,
+		meterProvider: mp,
+		initialized:   true,
+	}
+
+	err = sp.initMetrics(mp.Meter("spectra"))
+	if err != nil {
+		return nil, err
+	}
+
+	return sp, nil
+}
+
+// UseMetricReader routes the test metrics of sp to reader until tb completes.
+// Only the metric options in opts, such as WithDurationBuckets, are applied.
+func UseMetricReader(tb testing.TB, sp *Spectra, reader sdkmetric.Reader, opts ...Option) {
+	tb.Helper()
+
+	cfg := NewConfig(opts...)
+	if cfg.DurationBuckets == nil {
+		cfg.DurationBuckets = defaultDurationBuckets()
+	}
+
+	mp := newMeterProvider(cfg, nil, reader)
+
+	previous := sp.metrics
+
+	err := sp.initMetrics(mp.Meter("spectra"))
+	if err != nil {
+		tb.Fatalf("create metrics: %v", err)
+	}
+
+	tb.Cleanup(func() {
+		sp.metrics = previous
+		_ = mp.Shutdown(context.Background())
+	})
+}
@@ -0,0 +1,315 @@
+package spectra
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"slices"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// insecureTLSConfig skips certificate verification for HTTPS endpoints.
+func insecureTLSConfig() *tls.Config {
+	return &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec // User explicitly requested insecure mode.
+	}
+}
+
+// unixTarget is the gRPC target for a unix socket endpoint. The passthrough
+// resolver hands it to unixDialer unchanged.
+func unixTarget(path string) string {
+	return "passthrough:///" + path
+}
+
+// unixDialer connects gRPC to the unix socket at path.
+func unixDialer(path string) grpc.DialOption {
+	return grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+
+		return dialer.DialContext(ctx, "unix", path)
+	})
+}
+
+// traceHTTPOptions builds the OTLP/HTTP trace exporter options.
+func traceHTTPOptions(cfg config, proto protocol, endpoint string) []otlptracehttp.Option {
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpoint),
+		otlptracehttp.WithTimeout(cfg.ExportTimeout),
+	}
+
+	switch {
+	case cfg.TLSConfig != nil:
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(cfg.TLSConfig))
+	case proto == protocolHTTP:
+		opts = append(opts, otlptracehttp.WithInsecure())
+	case cfg.Insecure:
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(insecureTLSConfig()))
+	}
+
+	if cfg.Retry != nil {
+		opts = append(opts, otlptracehttp.WithRetry(otlptracehttp.RetryConfig{
+			Enabled:         true,
+			InitialInterval: cfg.Retry.InitialInterval,
+			MaxInterval:     cfg.Retry.MaxInterval,
+			MaxElapsedTime:  cfg.Retry.MaxElapsedTime,
+		}))
+	}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+
+	if cfg.Compression == CompressionGzip {
+		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
+
+	return opts
+}
+
+// traceGRPCOptions builds the OTLP/gRPC trace exporter options. dialOpts are
+// applied after any configured with WithGRPCDialOptions.
+func traceGRPCOptions(cfg config, endpoint string, dialOpts ...grpc.DialOption) []otlptracegrpc.Option {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(endpoint),
+		otlptracegrpc.WithTimeout(cfg.ExportTimeout),
+	}
+
+	switch {
+	case cfg.TLSConfig != nil:
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(cfg.TLSConfig)))
+	case cfg.Insecure:
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	if cfg.Retry != nil {
+		opts = append(opts, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{
+			Enabled:         true,
+			InitialInterval: cfg.Retry.InitialInterval,
+			MaxInterval:     cfg.Retry.MaxInterval,
+			MaxElapsedTime:  cfg.Retry.MaxElapsedTime,
+		}))
+	}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+	}
+
+	if cfg.Compression == CompressionGzip {
+		opts = append(opts, otlptracegrpc.WithCompressor(string(cfg.Compression)))
+	}
+
+	dialOpts = append(slices.Clone(cfg.GRPCDialOptions), dialOpts...)
+	if len(dialOpts) > 0 {
+		opts = append(opts, otlptracegrpc.WithDialOption(dialOpts...))
+	}
+
+	return opts
+}
+
+// traceUnixOptions builds the OTLP/gRPC trace exporter options for a unix
+// socket. The connection is plaintext unless a TLS configuration is set.
+func traceUnixOptions(cfg config, path string) []otlptracegrpc.Option {
+	opts := traceGRPCOptions(cfg, unixTarget(path), unixDialer(path))
+	if cfg.TLSConfig == nil {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	return opts
+}
+
+// temporalitySelector returns the selector exporting metrics with temporality.
+// With delta temporality, up-down counters such as test.active stay
+// cumulative, since their deltas cannot be summed into a current value.
+func temporalitySelector(temporality metricdata.Temporality) metric.TemporalitySelector {
+	if temporality == metricdata.DeltaTemporality {
+		return metric.DeltaTemporalitySelector
+	}
+
+	return metric.CumulativeTemporalitySelector
+}
+
+// metricHTTPOptions builds the OTLP/HTTP metric exporter options.
+func metricHTTPOptions(cfg config, proto protocol, endpoint string) []otlpmetrichttp.Option {
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(endpoint),
+		otlpmetrichttp.WithTimeout(cfg.ExportTimeout),
+		otlpmetrichttp.WithTemporalitySelector(temporalitySelector(cfg.MetricTemporality)),
+	}
+
+	switch {
+	case cfg.TLSConfig != nil:
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(cfg.TLSConfig))
+	case proto == protocolHTTP:
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	case cfg.Insecure:
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(insecureTLSConfig()))
+	}
+
+	if cfg.Retry != nil {
+		opts = append(opts, otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{
+			Enabled:         true,
+			InitialInterval: cfg.Retry.InitialInterval,
+			MaxInterval:     cfg.Retry.MaxInterval,
+			MaxElapsedTime:  cfg.Retry.MaxElapsedTime,
+		}))
+	}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(cfg.Headers))
+	}
+
+	if cfg.Compression == CompressionGzip {
+		opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	}
+
+	return opts
+}
+
+// metricGRPCOptions builds the OTLP/gRPC metric exporter options. dialOpts are
+// applied after any configured with WithGRPCDialOptions.
+func metricGRPCOptions(cfg config, endpoint string, dialOpts ...grpc.DialOption) []otlpmetricgrpc.Option {
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(endpoint),
+		otlpmetricgrpc.WithTimeout(cfg.ExportTimeout),
+		otlpmetricgrpc.WithTemporalitySelector(temporalitySelector(cfg.MetricTemporality)),
+	}
+
+	switch {
+	case cfg.TLSConfig != nil:
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(cfg.TLSConfig)))
+	case cfg.Insecure:
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+
+	if cfg.Retry != nil {
+		opts = append(opts, otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig{
+			Enabled:         true,
+			InitialInterval: cfg.Retry.InitialInterval,
+			MaxInterval:     cfg.Retry.MaxInterval,
+			MaxElapsedTime:  cfg.Retry.MaxElapsedTime,
+		}))
+	}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(cfg.Headers))
+	}
+
+	if cfg.Compression == CompressionGzip {
+		opts = append(opts, otlpmetricgrpc.WithCompressor(string(cfg.Compression)))
+	}
+
+	dialOpts = append(slices.Clone(cfg.GRPCDialOptions), dialOpts...)
+	if len(dialOpts) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithDialOption(dialOpts...))
+	}
+
+	return opts
+}
+
+// metricUnixOptions builds the OTLP/gRPC metric exporter options for a unix
+// socket. The connection is plaintext unless a TLS configuration is set.
+func metricUnixOptions(cfg config, path string) []otlpmetricgrpc.Option {
+	opts := metricGRPCOptions(cfg, unixTarget(path), unixDialer(path))
+	if cfg.TLSConfig == nil {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+
+	return opts
+}
+
+// logHTTPOptions builds the OTLP/HTTP log exporter options.
+func logHTTPOptions(cfg config, proto protocol, endpoint string) []otlploghttp.Option {
+	opts := []otlploghttp.Option{
+		otlploghttp.WithEndpoint(endpoint),
+		otlploghttp.WithTimeout(cfg.ExportTimeout),
+	}
+
+	switch {
+	case cfg.TLSConfig != nil:
+		opts = append(opts, otlploghttp.WithTLSClientConfig(cfg.TLSConfig))
+	case proto == protocolHTTP:
+		opts = append(opts, otlploghttp.WithInsecure())
+	case cfg.Insecure:
+		opts = append(opts, otlploghttp.WithTLSClientConfig(insecureTLSConfig()))
+	}
+
+	if cfg.Retry != nil {
+		opts = append(opts, otlploghttp.WithRetry(otlploghttp.RetryConfig{
+			Enabled:         true,
+			InitialInterval: cfg.Retry.InitialInterval,
+			MaxInterval:     cfg.Retry.MaxInterval,
+			MaxElapsedTime:  cfg.Retry.MaxElapsedTime,
+		}))
+	}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlploghttp.WithHeaders(cfg.Headers))
+	}
+
+	if cfg.Compression == CompressionGzip {
+		opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+	}
+
+	return opts
+}
+
+// logGRPCOptions builds the OTLP/gRPC log exporter options. dialOpts are
+// applied after any configured with WithGRPCDialOptions.
+func logGRPCOptions(cfg config, endpoint string, dialOpts ...grpc.DialOption) []otlploggrpc.Option {
+	opts := []otlploggrpc.Option{
+		otlploggrpc.WithEndpoint(endpoint),
+		otlploggrpc.WithTimeout(cfg.ExportTimeout),
+	}
+
+	switch {
+	case cfg.TLSConfig != nil:
+		opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(cfg.TLSConfig)))
+	case cfg.Insecure:
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+
+	if cfg.Retry != nil {
+		opts = append(opts, otlploggrpc.WithRetry(otlploggrpc.RetryConfig{
+			Enabled:         true,
+			InitialInterval: cfg.Retry.InitialInterval,
+			MaxInterval:     cfg.Retry.MaxInterval,
+			MaxElapsedTime:  cfg.Retry.MaxElapsedTime,
+		}))
+	}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlploggrpc.WithHeaders(cfg.Headers))
+	}
+
+	if cfg.Compression == CompressionGzip {
+		opts = append(opts, otlploggrpc.WithCompressor(string(cfg.Compression)))
+	}
+
+	dialOpts = append(slices.Clone(cfg.GRPCDialOptions), dialOpts...)
+	if len(dialOpts) > 0 {
+		opts = append(opts, otlploggrpc.WithDialOption(dialOpts...))
+	}
+
+	return opts
+}
+
+// logUnixOptions builds the OTLP/gRPC log exporter options for a unix socket.
+// The connection is plaintext unless a TLS configuration is set.
+func logUnixOptions(cfg config, path string) []otlploggrpc.Option {
+	opts := logGRPCOptions(cfg, unixTarget(path), unixDialer(path))
+	if cfg.TLSConfig == nil {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+
+	return opts
+}
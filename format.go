@@ -1,6 +1,13 @@
 package spectra
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
 
 // formatArgs formats variadic arguments into a string.
 func formatArgs(args ...any) string {
@@ -11,3 +18,33 @@ func formatArgs(args ...any) string {
 func formatf(format string, args ...any) string {
 	return fmt.Sprintf(format, args...)
 }
+
+// formatKV formats message followed by kv as key=value pairs.
+func formatKV(message string, kv ...attribute.KeyValue) string {
+	var b strings.Builder
+
+	b.WriteString(message)
+
+	for _, attr := range kv {
+		fmt.Fprintf(&b, " %s=%s", attr.Key, attr.Value.Emit())
+	}
+
+	return b.String()
+}
+
+// millis returns d in fractional milliseconds.
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// errorFromArgs returns the first error in args, or an error carrying message
+// if none of the arguments is an error.
+func errorFromArgs(message string, args ...any) error {
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			return err
+		}
+	}
+
+	return errors.New(message) //nolint:err113 // Error mirrors the test's own failure message.
+}
@@ -0,0 +1,107 @@
+package spectra
+
+import (
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// F wraps testing.F with OpenTelemetry instrumentation.
+// The fuzz target gets a span like any test created with New, and every
+// execution of the fuzz function gets its own child span.
+type F struct {
+	*T
+
+	f *testing.F
+}
+
+// NewF creates a new instrumented fuzz test wrapper.
+//
+// Example:
+//
+//	func FuzzParse(f *testing.F) {
+//	    sf, err := sp.NewF(f)
+//	    if err != nil {
+//	        f.Fatalf("spectra: %v", err)
+//	    }
+//	    sf.Add([]byte("seed"))
+//	    sf.Fuzz(func(st *spectra.T, data []byte) {
+//	        parse(st.Context(), data)
+//	    })
+//	}
+func (s *Spectra) NewF(f *testing.F) (*F, error) {
+	f.Helper()
+
+	t, err := s.New(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &F{T: t, f: f}, nil
+}
+
+// Add adds the arguments to the seed corpus for the fuzz test.
+func (f *F) Add(args ...any) {
+	f.Helper()
+
+	f.f.Add(args...)
+}
+
+// Fuzz runs the fuzz function ff, like testing.F.Fuzz, except that its first
+// parameter must be *spectra.T instead of *testing.T. Each execution runs in a
+// child span recording the input size; failing inputs mark the span as error.
+func (f *F) Fuzz(ff any) {
+	f.Helper()
+
+	fn := reflect.ValueOf(ff)
+	fnType := fn.Type()
+
+	if fnType.Kind() != reflect.Func || fnType.IsVariadic() || fnType.NumOut() != 0 ||
+		fnType.NumIn() < 1 || fnType.In(0) != reflect.TypeFor[*T]() {
+		f.Fatal("spectra: Fuzz() requires func(*spectra.T, ...) with no return values")
+
+		return
+	}
+
+	in := make([]reflect.Type, fnType.NumIn())
+	in[0] = reflect.TypeFor[*testing.T]()
+
+	for i := 1; i < len(in); i++ {
+		in[i] = fnType.In(i)
+	}
+
+	wrapper := reflect.MakeFunc(reflect.FuncOf(in, nil, false), func(args []reflect.Value) []reflect.Value {
+		innerT, ok := args[0].Interface().(*testing.T)
+		if !ok {
+			return nil
+		}
+
+		innerT.Helper()
+
+		st := f.newSubtest(innerT, attribute.Int(attrFuzzInputBytes, fuzzInputSize(args[1:])))
+
+		defer st.RecoverPanic()
+
+		args[0] = reflect.ValueOf(st)
+		fn.Call(args)
+
+		return nil
+	})
+
+	f.f.Fuzz(wrapper.Interface())
+}
+
+// fuzzInputSize returns the total length of the string and []byte inputs.
+func fuzzInputSize(args []reflect.Value) int {
+	size := 0
+
+	for _, arg := range args {
+		switch arg.Kind() { //nolint:exhaustive // Only variable-length inputs contribute to the size.
+		case reflect.String, reflect.Slice:
+			size += arg.Len()
+		}
+	}
+
+	return size
+}
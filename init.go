@@ -3,25 +3,61 @@ package spectra
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
 )
 
-const defaultShutdownTimeout = 5 * time.Second
+const (
+	defaultShutdownTimeout = 5 * time.Second
+	defaultExportTimeout   = 10 * time.Second
+
+	// Default OTLP ports, used when an endpoint omits the port.
+	defaultGRPCPort = "4317"
+	defaultHTTPPort = "4318"
+)
+
+// Environment variables read when the corresponding option is omitted.
+const (
+	envEndpoint    = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envServiceName = "OTEL_SERVICE_NAME"
+	envTraceParent = "TRACEPARENT"
+	envTraceState  = "TRACESTATE"
+)
 
 var (
 	// ErrMissingServiceName is returned when ServiceName is not configured.
@@ -30,8 +66,34 @@ var (
 	// ErrMissingEndpoint is returned when Endpoint is not configured.
 	ErrMissingEndpoint = errors.New("endpoint is required")
 
-	// ErrInvalidEndpoint is returned when endpoint doesn't have a valid scheme.
-	ErrInvalidEndpoint = errors.New("endpoint must have scheme (grpc://, http://, or https://)")
+	// ErrInvalidEndpoint is returned when endpoint doesn't have a valid scheme
+	// or its host:port is malformed.
+	ErrInvalidEndpoint = errors.New("invalid endpoint")
+
+	// ErrInsecureWithTLS is returned when WithInsecure is combined with TLS options.
+	ErrInsecureWithTLS = errors.New("insecure cannot be combined with TLS configuration")
+
+	// ErrInvalidCACert is returned when the CA certificate file contains no PEM certificates.
+	ErrInvalidCACert = errors.New("no valid PEM certificates found in CA file")
+
+	// ErrInvalidCompression is returned when an unsupported compression is configured.
+	ErrInvalidCompression = errors.New("compression must be none or gzip")
+
+	// ErrDialOptionsRequireGRPC is returned when gRPC dial options are set but an
+	// enabled signal exports over a non-gRPC endpoint.
+	ErrDialOptionsRequireGRPC = errors.New("gRPC dial options require grpc:// or unix:// endpoints")
+
+	// ErrMetricReaderWithEndpoint is returned when WithMetricReader is combined
+	// with a metrics endpoint.
+	ErrMetricReaderWithEndpoint = errors.New("metric reader cannot be combined with a metrics endpoint")
+
+	// ErrMetricViewsWithoutMetrics is returned when metric views are set but
+	// metrics are disabled.
+	ErrMetricViewsWithoutMetrics = errors.New("metric views require metrics to be enabled")
+
+	// ErrConflictingSignalConfig is returned when a signal is disabled with
+	// WithoutTraces or WithoutMetrics but also configured by another option.
+	ErrConflictingSignalConfig = errors.New("options configure a disabled signal")
 
 	// ErrNotInitialized is returned when Spectra is used before initialization.
 	ErrNotInitialized = errors.New("spectra not initialized")
@@ -43,22 +105,83 @@ var (
 type protocol string
 
 const (
-	protocolGRPC  protocol = "grpc"
-	protocolHTTP  protocol = "http"
-	protocolHTTPS protocol = "https"
+	protocolGRPC   protocol = "grpc"
+	protocolHTTP   protocol = "http"
+	protocolHTTPS  protocol = "https"
+	protocolStdout protocol = "stdout"
+	protocolUnix   protocol = "unix"
 )
 
+// parseProtocol splits endpoint into its protocol and host:port, or the socket
+// path for unix endpoints. A missing port defaults to the OTLP port for the
+// protocol.
 func parseProtocol(endpoint string) (protocol, string, error) {
+	var (
+		proto       protocol
+		address     string
+		defaultPort string
+	)
+
 	switch {
 	case strings.HasPrefix(endpoint, "grpc://"):
-		return protocolGRPC, strings.TrimPrefix(endpoint, "grpc://"), nil
+		proto, address, defaultPort = protocolGRPC, strings.TrimPrefix(endpoint, "grpc://"), defaultGRPCPort
 	case strings.HasPrefix(endpoint, "http://"):
-		return protocolHTTP, strings.TrimPrefix(endpoint, "http://"), nil
+		proto, address, defaultPort = protocolHTTP, strings.TrimPrefix(endpoint, "http://"), defaultHTTPPort
 	case strings.HasPrefix(endpoint, "https://"):
-		return protocolHTTPS, strings.TrimPrefix(endpoint, "https://"), nil
+		proto, address, defaultPort = protocolHTTPS, strings.TrimPrefix(endpoint, "https://"), defaultHTTPPort
+	case strings.HasPrefix(endpoint, "stdout://"):
+		return protocolStdout, "", nil
+	case strings.HasPrefix(endpoint, "unix://"):
+		path := strings.TrimPrefix(endpoint, "unix://")
+		if path == "" {
+			return "", "", fmt.Errorf("%w: %q: %w", ErrInvalidEndpoint, endpoint, errMissingSocketPath)
+		}
+
+		return protocolUnix, path, nil
 	default:
-		return "", "", ErrInvalidEndpoint
+		return "", "", fmt.Errorf(
+			"%w: %q must have scheme grpc://, http://, https://, unix://, or stdout://", ErrInvalidEndpoint, endpoint,
+		)
+	}
+
+	hostPort, err := normalizeHostPort(address, defaultPort)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %q: %w", ErrInvalidEndpoint, endpoint, err)
+	}
+
+	return proto, hostPort, nil
+}
+
+var (
+	errMissingHost       = errors.New("missing host")
+	errInvalidPort       = errors.New("port must be a number between 1 and 65535")
+	errMissingSocketPath = errors.New("missing socket path")
+)
+
+// normalizeHostPort validates address as host[:port], filling in defaultPort
+// when the port is omitted.
+func normalizeHostPort(address, defaultPort string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		// Retry with the default port for addresses such as "host" or "[::1]".
+		var retryErr error
+
+		host, port, retryErr = net.SplitHostPort(address + ":" + defaultPort)
+		if retryErr != nil {
+			return "", fmt.Errorf("parse host:port: %w", err)
+		}
+	}
+
+	if host == "" {
+		return "", errMissingHost
 	}
+
+	portNumber, err := strconv.Atoi(port)
+	if err != nil || portNumber < 1 || portNumber > 65535 {
+		return "", fmt.Errorf("%w: %q", errInvalidPort, port)
+	}
+
+	return net.JoinHostPort(host, port), nil
 }
 
 // config holds configuration for spectra initialization.
@@ -67,17 +190,170 @@ type config struct {
 	// Can also be set via OTEL_SERVICE_NAME env var.
 	ServiceName string
 
-	// Endpoint is the OTLP collector endpoint. Required.
+	// Endpoint is the OTLP collector endpoint. Required unless every
+	// enabled signal has its own endpoint.
 	// Can also be set via OTEL_EXPORTER_OTLP_ENDPOINT env var.
 	Endpoint string
 
+	// TracesEndpoint overrides Endpoint for the trace exporter.
+	TracesEndpoint string
+
+	// MetricsEndpoint overrides Endpoint for the metric exporter.
+	MetricsEndpoint string
+
 	// Insecure disables TLS for the OTLP exporter.
 	Insecure bool
 
+	// TLSConfig is the TLS client configuration for https:// and grpc://
+	// endpoints. It also enables TLS for http:// and unix:// endpoints, which
+	// are plaintext otherwise. Mutually exclusive with Insecure.
+	TLSConfig *tls.Config
+
+	// CACertFile is a PEM file with CA certificates used to verify the collector.
+	// The loaded pool is set as RootCAs on TLSConfig.
+	CACertFile string
+
+	// ClientCertFile and ClientKeyFile are a PEM key pair presented to the
+	// collector for mutual TLS. The loaded certificate is added to TLSConfig.
+	ClientCertFile string
+	ClientKeyFile  string
+
+	// GRPCDialOptions are passed to the gRPC connection of every exporter.
+	// Only valid when all enabled signals use grpc:// or unix:// endpoints.
+	GRPCDialOptions []grpc.DialOption
+
+	// Headers are sent with every export request to the collector.
+	Headers map[string]string
+
+	// Compression is the compression used for export payloads.
+	// Defaults to no compression.
+	Compression Compression
+
 	// ShutdownTimeout is the timeout for graceful shutdown.
 	// Defaults to 5 seconds.
 	ShutdownTimeout time.Duration
 
+	// ExportTimeout bounds each export request to the collector.
+	// Defaults to 10 seconds, matching the OTLP exporter default.
+	ExportTimeout time.Duration
+
+	// DurationBuckets are the explicit histogram boundaries, in seconds,
+	// for test.duration. Defaults to 1ms, 10ms, 100ms, 1s and 10s.
+	DurationBuckets []float64
+
+	// MetricViews customize the instruments of the meter provider, e.g. to
+	// rename them or drop attributes. They apply in addition to the
+	// test.duration bucket view. Requires metrics to be enabled.
+	MetricViews []metric.View
+
+	// MetricTemporality is the temporality of metrics exported over OTLP.
+	// Defaults to cumulative.
+	MetricTemporality metricdata.Temporality
+
+	// MetricNameCardinalityLimit caps the distinct test.name values recorded
+	// on test metrics. Zero means no limit.
+	MetricNameCardinalityLimit int
+
+	// DisableTestNameMetricAttribute omits test.name from the test metrics.
+	// Spans keep it.
+	DisableTestNameMetricAttribute bool
+
+	// Retry overrides the exporters' retry backoff for failed exports.
+	// Defaults to the OTLP exporter defaults.
+	Retry *retryConfig
+
+	// DefaultAttributes are set on every test, subtest, setup and teardown
+	// span. SetAttributes on a test overrides them.
+	DefaultAttributes []attribute.KeyValue
+
+	// Resource replaces the resource spectra builds from the service name,
+	// environment and host. service.name is added if it is missing.
+	Resource *resource.Resource
+
+	// ProcessRuntimeDetectors adds the process and container resource
+	// detectors, e.g. for process.pid and container.id.
+	ProcessRuntimeDetectors bool
+
+	// SpanNamePrefix is prepended to test span names as "prefix/TestName",
+	// e.g. to tell apart same-named tests from different packages.
+	SpanNamePrefix string
+
+	// SpanNamer computes the span name of tests and subtests from their test
+	// name, before SpanNamePrefix is applied. The test.name attribute keeps
+	// the raw test name. Defaults to the test name itself.
+	SpanNamer func(testName string) string
+
+	// ContextAttributeExtractor returns attributes to set on test spans and
+	// spans started with StartSpan, from the context they are started in.
+	ContextAttributeExtractor func(ctx context.Context) []attribute.KeyValue
+
+	// Sampler decides which spans are recorded and exported.
+	// Defaults to sdktrace.AlwaysSample().
+	Sampler sdktrace.Sampler
+
+	// SpanLimits bounds the attributes, events and links kept per span.
+	// Defaults to the SDK limits, which honor the OTEL_SPAN_*_LIMIT variables.
+	SpanLimits *sdktrace.SpanLimits
+
+	// MetricReader collects metrics instead of a periodic reader exporting to
+	// MetricsEndpoint. It cannot be combined with MetricsEndpoint.
+	MetricReader metric.Reader
+
+	// PrometheusExporter serves metrics for scraping through
+	// Spectra.MetricsHandler instead of pushing them to MetricsEndpoint,
+	// which is then ignored.
+	PrometheusExporter bool
+
+	// SpanExporter receives spans instead of an OTLP exporter built from
+	// TracesEndpoint, which is then not required.
+	SpanExporter sdktrace.SpanExporter
+
+	// JSONSummary receives a JSON array of every test's name, status,
+	// duration and trace id on Shutdown.
+	JSONSummary io.Writer
+
+	// SpanProcessors are registered on the tracer provider before the
+	// batching exporter, in the order given.
+	SpanProcessors []sdktrace.SpanProcessor
+
+	// Propagator is installed as the global text map propagator.
+	// Defaults to a composite of W3C trace context and baggage.
+	Propagator propagation.TextMapPropagator
+
+	// JUnitReport receives a JUnit XML report of every test and subtest on
+	// Shutdown.
+	JUnitReport io.Writer
+
+	// TestEndHook is called with the result of each test created by New,
+	// after its span has ended.
+	TestEndHook func(TestResult)
+
+	// DisableGlobalProviders keeps the providers, propagator and error handler
+	// on the Spectra instance instead of installing them as otel globals, so
+	// several instances can coexist.
+	DisableGlobalProviders bool
+
+	// AttributesEnvPrefix selects environment variables that are attached to
+	// every test span as attributes, e.g. "CI_".
+	AttributesEnvPrefix string
+
+	// CIContextFromEnv makes test spans children of the trace context in the
+	// TRACEPARENT and TRACESTATE environment variables, extracted with
+	// Propagator.
+	CIContextFromEnv bool
+
+	// Logger receives spectra's internal diagnostics, such as export errors
+	// reported by the OpenTelemetry SDK. Defaults to log.Printf.
+	Logger func(format string, args ...any)
+
+	// LogScrubber rewrites each captured log message before it is recorded,
+	// e.g. to redact secrets. Defaults to returning the message unchanged.
+	LogScrubber func(message string) string
+
+	// Disabled turns spectra into a no-op: no exporters or providers are
+	// built and ServiceName and Endpoint are not required.
+	Disabled bool
+
 	// DisableTraces disables trace collection.
 	DisableTraces bool
 
@@ -86,15 +362,61 @@ type config struct {
 
 	// DisableLogs disables log capture as span events.
 	DisableLogs bool
+
+	// DebugLogs records Debugf messages as span events.
+	DebugLogs bool
+
+	// CleanupTracing records cleanup.registered and cleanup.run events on
+	// the test span for each function registered with Cleanup.
+	CleanupTracing bool
+
+	// CodeLocation sets the code.filepath and code.lineno attributes on test
+	// spans to where New was called.
+	CodeLocation bool
+
+	// MemoryProfiling sets test.alloc_bytes on test spans to the bytes
+	// allocated while the test ran.
+	MemoryProfiling bool
+
+	// GoroutineLeakCheck records a test.goroutine_leak event on test spans
+	// when more goroutines run after the test than when New was called.
+	// GoroutineLeakFail also fails the test.
+	GoroutineLeakCheck bool
+	GoroutineLeakFail  bool
+
+	// StdLogCapture records lines written through the standard library logger
+	// while a test runs as log events on that test's span.
+	StdLogCapture bool
+
+	// ExportLogs emits captured logs as OTLP log records to Endpoint, in
+	// addition to span events.
+	ExportLogs bool
+}
+
+// retryConfig is the backoff applied by every exporter when an export fails.
+type retryConfig struct {
+	// InitialInterval is the wait after the first failure.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the wait between retries.
+	MaxInterval time.Duration
+
+	// MaxElapsedTime is the total time spent retrying before a batch is dropped.
+	MaxElapsedTime time.Duration
 }
 
 // Init initializes OpenTelemetry providers for test instrumentation.
 // It returns a Spectra instance that manages the telemetry lifecycle.
 //
-// ServiceName and Endpoint are required. Endpoint must include a scheme:
+// ServiceName and Endpoint are required. If WithServiceName or WithEndpoint is
+// omitted, the OTEL_SERVICE_NAME or OTEL_EXPORTER_OTLP_ENDPOINT environment
+// variable is used instead. WithTracesEndpoint and WithMetricsEndpoint override
+// the endpoint for a single signal. Endpoints must include a scheme:
 //   - grpc://host:port - gRPC protocol
 //   - http://host:port - HTTP protocol (no TLS)
 //   - https://host:port - HTTPS protocol (TLS)
+//   - unix:///path/to/socket - gRPC over a unix domain socket
+//   - stdout:// - pretty-print to stderr, no collector required
 //
 // Example:
 //
@@ -106,8 +428,11 @@ type config struct {
 //	    if err != nil {
 //	        log.Fatalf("spectra init: %v", err)
 //	    }
-//	    defer sp.Shutdown()
-//	    os.Exit(m.Run())
+//	    code := m.Run()
+//	    if err := sp.Shutdown(); err != nil {
+//	        log.Printf("spectra shutdown: %v", err)
+//	    }
+//	    os.Exit(code)
 //	}
 func Init(opts ...Option) (*Spectra, error) {
 	cfg := config{}
@@ -115,6 +440,10 @@ func Init(opts ...Option) (*Spectra, error) {
 		opt(&cfg)
 	}
 
+	if cfg.Disabled {
+		return newDisabled(), nil
+	}
+
 	cfg, err := validateConfig(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
@@ -151,22 +480,134 @@ func Init(opts ...Option) (*Spectra, error) {
 		sp.meterProvider = mp
 	}
 
+	if cfg.ExportLogs && !cfg.DisableLogs {
+		lp, err := setupLogging(ctx, cfg, res)
+		if err != nil {
+			return nil, fmt.Errorf("setup logging: %w", err)
+		}
+
+		sp.loggerProvider = lp
+		sp.logger = lp.Logger("spectra")
+	}
+
+	if cfg.CIContextFromEnv {
+		sp.parentCtx = contextFromEnv(cfg.Propagator)
+	}
+
+	sp.envAttributes = attributesFromEnv(cfg.AttributesEnvPrefix)
+
+	if cfg.JUnitReport != nil {
+		sp.junit = newJUnitReport(cfg.JUnitReport, cfg.ServiceName)
+	}
+
+	if cfg.JSONSummary != nil {
+		sp.summary = newJSONSummary(cfg.JSONSummary)
+	}
+
+	if !cfg.DisableGlobalProviders {
+		otel.SetTextMapPropagator(cfg.Propagator)
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+			cfg.Logger("spectra: %v", err)
+		}))
+	}
+
 	return sp, nil
 }
 
-// createResource creates the OTEL resource with service info.
+// newDisabled returns a Spectra backed by the no-op tracer and without
+// metrics or logs, so tests run instrumented code without a collector. Every
+// other option is dropped, so hooks, reports, log capture and the goroutine
+// and memory checks are off too.
+func newDisabled() *Spectra {
+	return &Spectra{
+		config: config{
+			Disabled:       true,
+			DisableTraces:  true,
+			DisableMetrics: true,
+			DisableLogs:    true,
+		},
+		tracer:      noop.NewTracerProvider().Tracer("spectra"),
+		initialized: true,
+	}
+}
+
+// contextFromEnv extracts the trace context a CI system exported in the
+// TRACEPARENT and TRACESTATE environment variables.
+func contextFromEnv(propagator propagation.TextMapPropagator) context.Context {
+	carrier := propagation.MapCarrier{
+		"traceparent": os.Getenv(envTraceParent),
+		"tracestate":  os.Getenv(envTraceState),
+	}
+
+	return propagator.Extract(context.Background(), carrier)
+}
+
+// attributesFromEnv returns an attribute for every environment variable named
+// with prefix. Keys are lowercased with the prefix stripped and underscores
+// replaced by dots, so CI_JOB_ID with prefix CI_ becomes job.id.
+func attributesFromEnv(prefix string) []attribute.KeyValue {
+	if prefix == "" {
+		return nil
+	}
+
+	var attrs []attribute.KeyValue
+
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+
+		suffix, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+
+		key := strings.ToLower(strings.ReplaceAll(strings.Trim(suffix, "_"), "_", "."))
+		if key == "" {
+			continue
+		}
+
+		attrs = append(attrs, attribute.String(key, value))
+	}
+
+	slices.SortFunc(attrs, func(a, b attribute.KeyValue) int {
+		return strings.Compare(string(a.Key), string(b.Key))
+	})
+
+	return attrs
+}
+
+// createResource creates the OTEL resource with service info. A resource set
+// with WithResource is used as is, with service.name added if it lacks one.
 func createResource(cfg config) (*resource.Resource, error) {
-	res, err := resource.New(
-		context.Background(),
+	if cfg.Resource != nil {
+		if _, ok := cfg.Resource.Set().Value(semconv.ServiceNameKey); ok {
+			return cfg.Resource, nil
+		}
+
+		res, err := resource.Merge(cfg.Resource, resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName)))
+		if err != nil {
+			return nil, fmt.Errorf("create resource: %w", err)
+		}
+
+		return res, nil
+	}
+
+	opts := []resource.Option{
 		resource.WithAttributes(
 			semconv.ServiceName(cfg.ServiceName),
 			semconv.ServiceVersion("test"),
+			attribute.String(attrTestFramework, "spectra"),
 		),
 		resource.WithFromEnv(),
 		resource.WithTelemetrySDK(),
 		resource.WithHost(),
-	)
-	if err != nil {
+	}
+
+	if cfg.ProcessRuntimeDetectors {
+		opts = append(opts, resource.WithProcess(), resource.WithContainer())
+	}
+
+	res, err := resource.New(context.Background(), opts...)
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
 		return nil, fmt.Errorf("create resource: %w", err)
 	}
 
@@ -175,58 +616,84 @@ func createResource(cfg config) (*resource.Resource, error) {
 
 // setupTracing configures the trace provider and returns a shutdown function.
 func setupTracing(ctx context.Context, cfg config, res *resource.Resource) (*sdktrace.TracerProvider, func(), error) {
-	proto, endpoint, err := parseProtocol(cfg.Endpoint)
+	exporter := cfg.SpanExporter
+	if exporter == nil {
+		var err error
+
+		exporter, err = newSpanExporter(ctx, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tp := newTracerProvider(cfg, res, exporter)
+	if !cfg.DisableGlobalProviders {
+		otel.SetTracerProvider(tp)
+	}
+
+	//nolint:contextcheck // Shutdown uses fresh context with timeout, not the init context.
+	return tp, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		err := tp.Shutdown(shutdownCtx)
+		if err != nil {
+			cfg.Logger("spectra: failed to shutdown tracer provider: %v", err)
+		}
+	}, nil
+}
+
+// newSpanExporter creates the span exporter for cfg.TracesEndpoint.
+func newSpanExporter(ctx context.Context, cfg config) (sdktrace.SpanExporter, error) {
+	proto, endpoint, err := parseProtocol(cfg.TracesEndpoint)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	var exporter sdktrace.SpanExporter
 
 	switch proto {
-	case protocolHTTP:
-		exporter, err = otlptracehttp.New(ctx,
-			otlptracehttp.WithEndpoint(endpoint),
-			otlptracehttp.WithInsecure(),
-		)
-	case protocolHTTPS:
-		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
-		if cfg.Insecure {
-			opts = append(opts, otlptracehttp.WithTLSClientConfig(&tls.Config{
-				InsecureSkipVerify: true, //nolint:gosec // User explicitly requested insecure mode.
-			}))
-		}
-
-		exporter, err = otlptracehttp.New(ctx, opts...)
+	case protocolHTTP, protocolHTTPS:
+		exporter, err = otlptracehttp.New(ctx, traceHTTPOptions(cfg, proto, endpoint)...)
 	case protocolGRPC:
-		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
-		if cfg.Insecure {
-			opts = append(opts, otlptracegrpc.WithInsecure())
-		}
-
-		exporter, err = otlptracegrpc.New(ctx, opts...)
+		exporter, err = otlptracegrpc.New(ctx, traceGRPCOptions(cfg, endpoint)...)
+	case protocolUnix:
+		exporter, err = otlptracegrpc.New(ctx, traceUnixOptions(cfg, endpoint)...)
+	case protocolStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr), stdouttrace.WithPrettyPrint())
 	}
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("create trace exporter: %w", err)
+		return nil, fmt.Errorf("create trace exporter: %w", err)
 	}
 
-	tp := sdktrace.NewTracerProvider(
+	return exporter, nil
+}
+
+// newTracerProvider creates a tracer provider that batches spans to exporter.
+// User span processors are registered first, so they see each span before
+// the batcher.
+func newTracerProvider(
+	cfg config,
+	res *resource.Resource,
+	exporter sdktrace.SpanExporter,
+) *sdktrace.TracerProvider {
+	opts := make([]sdktrace.TracerProviderOption, 0, len(cfg.SpanProcessors)+4)
+	for _, processor := range cfg.SpanProcessors {
+		opts = append(opts, sdktrace.WithSpanProcessor(processor))
+	}
+
+	opts = append(opts,
 		sdktrace.WithBatcher(exporter),
 		sdktrace.WithResource(res),
+		sdktrace.WithSampler(forceSampler{cfg.Sampler}),
 	)
-	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(propagation.TraceContext{})
 
-	//nolint:contextcheck // Shutdown uses fresh context with timeout, not the init context.
-	return tp, func() {
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
-		defer cancel()
+	if cfg.SpanLimits != nil {
+		opts = append(opts, sdktrace.WithSpanLimits(*cfg.SpanLimits))
+	}
 
-		err := tp.Shutdown(shutdownCtx)
-		if err != nil {
-			log.Printf("spectra: failed to shutdown tracer provider: %v", err)
-		}
-	}, nil
+	return sdktrace.NewTracerProvider(opts...)
 }
 
 // setupMetrics configures the meter provider and returns a shutdown function.
@@ -236,77 +703,395 @@ func setupMetrics(
 	res *resource.Resource,
 	sp *Spectra,
 ) (*metric.MeterProvider, func(), error) {
-	proto, endpoint, err := parseProtocol(cfg.Endpoint)
+	reader, handler, err := newMetricReader(ctx, cfg)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var exporter metric.Exporter
+	sp.metricsHandler = handler
 
-	switch proto {
-	case protocolHTTP:
-		exporter, err = otlpmetrichttp.New(ctx,
-			otlpmetrichttp.WithEndpoint(endpoint),
-			otlpmetrichttp.WithInsecure(),
-		)
-	case protocolHTTPS:
-		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(endpoint)}
-		if cfg.Insecure {
-			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(&tls.Config{
-				InsecureSkipVerify: true, //nolint:gosec // User explicitly requested insecure mode.
-			}))
+	mp := newMeterProvider(cfg, res, reader)
+	if !cfg.DisableGlobalProviders {
+		otel.SetMeterProvider(mp)
+	}
+
+	err = sp.initMetrics(mp.Meter("spectra"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("init metrics: %w", err)
+	}
+
+	//nolint:contextcheck // Shutdown uses fresh context with timeout, not the init context.
+	return mp, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		err := mp.Shutdown(shutdownCtx)
+		if err != nil {
+			cfg.Logger("spectra: failed to shutdown meter provider: %v", err)
 		}
+	}, nil
+}
+
+// newMetricReader returns the reader metrics are collected with: the
+// configured reader, a Prometheus exporter along with the handler serving it,
+// or a periodic reader exporting to cfg.MetricsEndpoint.
+func newMetricReader(ctx context.Context, cfg config) (metric.Reader, http.Handler, error) {
+	switch {
+	case cfg.MetricReader != nil:
+		return cfg.MetricReader, nil, nil
+	case cfg.PrometheusExporter:
+		return newPrometheusReader()
+	}
+
+	exporter, err := newMetricExporter(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return metric.NewPeriodicReader(exporter), nil, nil
+}
+
+// newMetricExporter creates the metric exporter for cfg.MetricsEndpoint.
+func newMetricExporter(ctx context.Context, cfg config) (metric.Exporter, error) {
+	proto, endpoint, err := parseProtocol(cfg.MetricsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var exporter metric.Exporter
 
-		exporter, err = otlpmetrichttp.New(ctx, opts...)
+	switch proto {
+	case protocolHTTP, protocolHTTPS:
+		exporter, err = otlpmetrichttp.New(ctx, metricHTTPOptions(cfg, proto, endpoint)...)
 	case protocolGRPC:
-		opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(endpoint)}
-		if cfg.Insecure {
-			opts = append(opts, otlpmetricgrpc.WithInsecure())
-		}
+		exporter, err = otlpmetricgrpc.New(ctx, metricGRPCOptions(cfg, endpoint)...)
+	case protocolUnix:
+		exporter, err = otlpmetricgrpc.New(ctx, metricUnixOptions(cfg, endpoint)...)
+	case protocolStdout:
+		exporter, err = stdoutmetric.New(stdoutmetric.WithWriter(os.Stderr), stdoutmetric.WithPrettyPrint())
+	}
 
-		exporter, err = otlpmetricgrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create metric exporter: %w", err)
 	}
 
+	return exporter, nil
+}
+
+// newPrometheusReader creates a Prometheus exporter on its own registry, so
+// several instances don't collide on the default one, and the handler that
+// serves it.
+func newPrometheusReader() (metric.Reader, http.Handler, error) {
+	registry := prometheus.NewRegistry()
+
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
 	if err != nil {
-		return nil, nil, fmt.Errorf("create metric exporter: %w", err)
+		return nil, nil, fmt.Errorf("create prometheus exporter: %w", err)
 	}
 
-	mp := metric.NewMeterProvider(
-		metric.WithReader(metric.NewPeriodicReader(exporter)),
+	return exporter, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
+
+// newMeterProvider creates a meter provider that collects through reader, with
+// test.duration bucketed by cfg.DurationBuckets and cfg.MetricViews applied.
+func newMeterProvider(cfg config, res *resource.Resource, reader metric.Reader) *metric.MeterProvider {
+	return metric.NewMeterProvider(
+		metric.WithReader(reader),
 		metric.WithResource(res),
+		metric.WithView(metric.NewView(
+			metric.Instrument{Name: metricTestDuration},
+			metric.Stream{Aggregation: metric.AggregationExplicitBucketHistogram{
+				Boundaries: cfg.DurationBuckets,
+			}},
+		)),
+		metric.WithView(cfg.MetricViews...),
 	)
-	otel.SetMeterProvider(mp)
+}
 
-	err = sp.initMetrics()
+// setupLogging configures the logger provider for OTLP log export.
+func setupLogging(ctx context.Context, cfg config, res *resource.Resource) (*sdklog.LoggerProvider, error) {
+	proto, endpoint, err := parseProtocol(cfg.Endpoint)
 	if err != nil {
-		return nil, nil, fmt.Errorf("init metrics: %w", err)
+		return nil, err
 	}
 
-	//nolint:contextcheck // Shutdown uses fresh context with timeout, not the init context.
-	return mp, func() {
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
-		defer cancel()
+	var exporter sdklog.Exporter
 
-		err := mp.Shutdown(shutdownCtx)
-		if err != nil {
-			log.Printf("spectra: failed to shutdown meter provider: %v", err)
-		}
-	}, nil
+	switch proto {
+	case protocolHTTP, protocolHTTPS:
+		exporter, err = otlploghttp.New(ctx, logHTTPOptions(cfg, proto, endpoint)...)
+	case protocolGRPC:
+		exporter, err = otlploggrpc.New(ctx, logGRPCOptions(cfg, endpoint)...)
+	case protocolUnix:
+		exporter, err = otlploggrpc.New(ctx, logUnixOptions(cfg, endpoint)...)
+	case protocolStdout:
+		exporter, err = stdoutlog.New(stdoutlog.WithWriter(os.Stderr), stdoutlog.WithPrettyPrint())
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("create log exporter: %w", err)
+	}
+
+	lp := newLoggerProvider(res, exporter)
+	if !cfg.DisableGlobalProviders {
+		global.SetLoggerProvider(lp)
+	}
+
+	return lp, nil
+}
+
+// newLoggerProvider creates a logger provider that batches records to exporter.
+func newLoggerProvider(res *resource.Resource, exporter sdklog.Exporter) *sdklog.LoggerProvider {
+	return sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(res),
+	)
 }
 
 // validateConfig validates required fields and sets defaults.
 func validateConfig(cfg config) (config, error) {
 	if cfg.ServiceName == "" {
-		return cfg, ErrMissingServiceName
+		cfg.ServiceName = os.Getenv(envServiceName)
+		if cfg.ServiceName == "" {
+			return cfg, ErrMissingServiceName
+		}
 	}
 
-	if cfg.Endpoint == "" {
-		return cfg, ErrMissingEndpoint
+	err := validateSignals(cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg, err = resolveEndpoints(cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg, err = resolveTLS(cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	if len(cfg.MetricViews) > 0 && cfg.DisableMetrics {
+		return cfg, ErrMetricViewsWithoutMetrics
+	}
+
+	if len(cfg.GRPCDialOptions) > 0 {
+		err = validateGRPCEndpoints(cfg)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	switch cfg.Compression {
+	case CompressionNone, CompressionGzip:
+	default:
+		return cfg, fmt.Errorf("%w: %q", ErrInvalidCompression, cfg.Compression)
 	}
 
 	if cfg.ShutdownTimeout == 0 {
 		cfg.ShutdownTimeout = defaultShutdownTimeout
 	}
 
+	if cfg.ExportTimeout == 0 {
+		cfg.ExportTimeout = defaultExportTimeout
+	}
+
+	if cfg.DurationBuckets == nil {
+		cfg.DurationBuckets = defaultDurationBuckets()
+	}
+
+	if cfg.Sampler == nil {
+		cfg.Sampler = sdktrace.AlwaysSample()
+	}
+
+	if cfg.Propagator == nil {
+		cfg.Propagator = propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		)
+	}
+
+	if cfg.Logger == nil {
+		cfg.Logger = log.Printf
+	}
+
+	if cfg.LogScrubber == nil {
+		cfg.LogScrubber = func(message string) string { return message }
+	}
+
+	return cfg, nil
+}
+
+// validateSignals rejects options that configure a signal disabled with
+// WithoutTraces or WithoutMetrics, which would otherwise be ignored silently.
+func validateSignals(cfg config) error {
+	var conflicts []string
+
+	if cfg.DisableTraces {
+		for option, set := range map[string]bool{
+			"WithTracesEndpoint": cfg.TracesEndpoint != "",
+			"WithSpanExporter":   cfg.SpanExporter != nil,
+			"WithSpanProcessor":  len(cfg.SpanProcessors) > 0,
+		} {
+			if set {
+				conflicts = append(conflicts, "WithoutTraces with "+option)
+			}
+		}
+	}
+
+	if cfg.DisableMetrics {
+		for option, set := range map[string]bool{
+			"WithMetricsEndpoint":    cfg.MetricsEndpoint != "",
+			"WithMetricReader":       cfg.MetricReader != nil,
+			"WithPrometheusExporter": cfg.PrometheusExporter,
+		} {
+			if set {
+				conflicts = append(conflicts, "WithoutMetrics with "+option)
+			}
+		}
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	slices.Sort(conflicts)
+
+	return fmt.Errorf("%w: %s", ErrConflictingSignalConfig, strings.Join(conflicts, ", "))
+}
+
+// resolveEndpoints applies the shared endpoint to signals without their own
+// and validates the endpoint of every enabled signal.
+func resolveEndpoints(cfg config) (config, error) {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = os.Getenv(envEndpoint)
+	}
+
+	if cfg.TracesEndpoint == "" {
+		cfg.TracesEndpoint = cfg.Endpoint
+	}
+
+	if cfg.MetricReader != nil && cfg.MetricsEndpoint != "" {
+		return cfg, ErrMetricReaderWithEndpoint
+	}
+
+	customReader := cfg.MetricReader != nil || cfg.PrometheusExporter
+
+	if cfg.MetricsEndpoint == "" && !customReader {
+		cfg.MetricsEndpoint = cfg.Endpoint
+	}
+
+	if !cfg.DisableTraces && cfg.SpanExporter == nil {
+		err := validateEndpoint(cfg.TracesEndpoint)
+		if err != nil {
+			return cfg, fmt.Errorf("traces: %w", err)
+		}
+	}
+
+	if !cfg.DisableMetrics && !customReader {
+		err := validateEndpoint(cfg.MetricsEndpoint)
+		if err != nil {
+			return cfg, fmt.Errorf("metrics: %w", err)
+		}
+	}
+
+	if cfg.ExportLogs && !cfg.DisableLogs {
+		err := validateEndpoint(cfg.Endpoint)
+		if err != nil {
+			return cfg, fmt.Errorf("logs: %w", err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// validateGRPCEndpoints checks that every enabled signal exports over gRPC.
+func validateGRPCEndpoints(cfg config) error {
+	signals := []struct {
+		name     string
+		endpoint string
+		enabled  bool
+	}{
+		{name: "traces", endpoint: cfg.TracesEndpoint, enabled: !cfg.DisableTraces && cfg.SpanExporter == nil},
+		{
+			name:     "metrics",
+			endpoint: cfg.MetricsEndpoint,
+			enabled:  !cfg.DisableMetrics && cfg.MetricReader == nil && !cfg.PrometheusExporter,
+		},
+		{name: "logs", endpoint: cfg.Endpoint, enabled: cfg.ExportLogs && !cfg.DisableLogs},
+	}
+
+	for _, signal := range signals {
+		if !signal.enabled {
+			continue
+		}
+
+		proto, _, err := parseProtocol(signal.endpoint)
+		if err != nil {
+			return fmt.Errorf("%s: %w", signal.name, err)
+		}
+
+		if proto != protocolGRPC && proto != protocolUnix {
+			return fmt.Errorf("%s: %w: got %q", signal.name, ErrDialOptionsRequireGRPC, signal.endpoint)
+		}
+	}
+
+	return nil
+}
+
+// validateEndpoint checks that endpoint is set and has a supported scheme.
+func validateEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return ErrMissingEndpoint
+	}
+
+	_, _, err := parseProtocol(endpoint)
+
+	return err
+}
+
+// resolveTLS loads TLS files referenced by the config into TLSConfig.
+func resolveTLS(cfg config) (config, error) {
+	hasFiles := cfg.CACertFile != "" || cfg.ClientCertFile != "" || cfg.ClientKeyFile != ""
+
+	if cfg.Insecure && (cfg.TLSConfig != nil || hasFiles) {
+		return cfg, ErrInsecureWithTLS
+	}
+
+	if !hasFiles {
+		return cfg, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.TLSConfig != nil {
+		tlsConfig = cfg.TLSConfig.Clone()
+	}
+
+	if cfg.CACertFile != "" {
+		pemData, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return cfg, fmt.Errorf("read CA cert file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemData) {
+			return cfg, fmt.Errorf("%w: %s", ErrInvalidCACert, cfg.CACertFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertFile != "" || cfg.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return cfg, fmt.Errorf("load client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	}
+
+	cfg.TLSConfig = tlsConfig
+
 	return cfg, nil
 }
@@ -0,0 +1,138 @@
+package spectra
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// junitReport collects test results and writes them as JUnit XML.
+type junitReport struct {
+	w     io.Writer
+	suite string
+
+	mu      sync.Mutex
+	results []TestResult
+}
+
+func newJUnitReport(w io.Writer, suite string) *junitReport {
+	return &junitReport{w: w, suite: suite}
+}
+
+func (r *junitReport) record(result TestResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.results = append(r.results, result)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// write writes the recorded results to w as a <testsuites> document with a
+// single suite. Subtests use their parent test as classname, top-level tests
+// the suite name.
+func (r *junitReport) write() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	suite := junitTestSuite{Name: r.suite, TestCases: make([]junitTestCase, 0, len(r.results))}
+
+	var total float64
+
+	for _, result := range r.results {
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: r.suite,
+			Time:      formatSeconds(result.Duration.Seconds()),
+		}
+
+		if result.Parent != "" {
+			testCase.ClassName = result.Parent
+		}
+
+		switch result.Status {
+		case statusFail:
+			testCase.Failure = junitFailureFor(result.Errors)
+			suite.Failures++
+		case statusSkip:
+			testCase.Skipped = &struct{}{}
+			suite.Skipped++
+		}
+
+		if result.Parent == "" {
+			total += result.Duration.Seconds()
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	suite.Tests = len(suite.TestCases)
+	suite.Time = formatSeconds(total)
+
+	doc := junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	_, err := io.WriteString(r.w, xml.Header)
+	if err != nil {
+		return fmt.Errorf("write junit report: %w", err)
+	}
+
+	encoder := xml.NewEncoder(r.w)
+	encoder.Indent("", "  ")
+
+	err = encoder.Encode(doc)
+	if err != nil {
+		return fmt.Errorf("write junit report: %w", err)
+	}
+
+	return nil
+}
+
+// junitFailureFor builds the failure element from the logged error messages.
+func junitFailureFor(messages []string) *junitFailure {
+	if len(messages) == 0 {
+		return &junitFailure{Message: "test failed"}
+	}
+
+	return &junitFailure{Message: messages[0], Text: strings.Join(messages, "\n")}
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
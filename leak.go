@@ -0,0 +1,48 @@
+package spectra
+
+import (
+	"runtime"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// goroutineLeakSettle is how long a test's cleanup waits for goroutines
+	// that are still exiting before reporting a leak.
+	goroutineLeakSettle = 100 * time.Millisecond
+
+	// goroutineLeakPoll is how often the goroutine count is checked while
+	// waiting.
+	goroutineLeakPoll = 10 * time.Millisecond
+)
+
+// checkGoroutineLeak records a goroutine leak event on t if more goroutines
+// are running than the before count taken at New, once exiting goroutines
+// have had time to finish. The runtime's own system goroutines are not
+// counted by runtime.NumGoroutine, so they cannot cause a leak report.
+func (t *T) checkGoroutineLeak(before int) {
+	deadline := time.Now().Add(goroutineLeakSettle)
+
+	after := runtime.NumGoroutine()
+	for after > before && time.Now().Before(deadline) {
+		time.Sleep(goroutineLeakPoll)
+
+		after = runtime.NumGoroutine()
+	}
+
+	if after <= before {
+		return
+	}
+
+	t.Span().AddEvent(leakEventName, trace.WithAttributes(
+		attribute.Int(attrGoroutinesBefore, before),
+		attribute.Int(attrGoroutinesAfter, after),
+	))
+
+	if t.spectra.config.GoroutineLeakFail {
+		t.setFailed()
+		t.TB.Errorf("spectra: %d goroutine(s) still running after the test", after-before)
+	}
+}
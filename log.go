@@ -0,0 +1,49 @@
+package spectra
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+// emitLog emits message as an OTLP log record with attrs when log export is
+// enabled. The record carries the test's span context so backends can
+// correlate it.
+func (t *T) emitLog(message, level string, attrs ...attribute.KeyValue) {
+	if t.spectra == nil || t.spectra.logger == nil {
+		return
+	}
+
+	var record otellog.Record
+
+	record.SetTimestamp(time.Now())
+	record.SetSeverity(logSeverity(level))
+	record.SetSeverityText(level)
+	record.SetBody(otellog.StringValue(message))
+	record.AddAttributes(otellog.String(attrTestName, t.Name()))
+
+	for _, attr := range attrs {
+		record.AddAttributes(otellog.KeyValueFromAttribute(attr))
+	}
+
+	t.spectra.logger.Emit(t.Context(), record)
+}
+
+// logSeverity maps a test log level to its OTel severity: info is INFO (9),
+// warn is WARN (13), error is ERROR (17), fatal is FATAL (21), and debug and
+// skip are DEBUG (5).
+func logSeverity(level string) otellog.Severity {
+	switch level {
+	case levelWarn:
+		return otellog.SeverityWarn
+	case levelError:
+		return otellog.SeverityError
+	case levelFatal:
+		return otellog.SeverityFatal
+	case levelDebug, levelSkip:
+		return otellog.SeverityDebug
+	default:
+		return otellog.SeverityInfo
+	}
+}
@@ -3,75 +3,269 @@ package spectra
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
 )
 
-var (
-	metricsOnce sync.Once //nolint:gochecknoglobals // Singleton initialization.
-	testMetrics *Metrics  //nolint:gochecknoglobals // Global metrics instance.
+// Metric instrument names.
+const (
+	metricTestDuration = "test.duration"
+	metricTestCount    = "test.count"
+	metricTestFailures = "test.failures"
+	metricTestActive   = "test.active"
 )
 
+// otherTestName replaces test names in metrics once the cardinality limit is
+// reached.
+const otherTestName = "other"
+
 // Metrics holds the test metrics instruments.
 type Metrics struct {
 	duration metric.Float64Histogram
 	count    metric.Int64Counter
+	failures metric.Int64Counter
+	active   metric.Int64UpDownCounter
+
+	meter  metric.Meter
+	mu     sync.Mutex
+	values map[string]metric.Float64Histogram
+	names  map[string]struct{}
 }
 
-// initMetrics initializes the metrics instruments.
+// initMetrics creates the metrics instruments for s from meter, so each
+// Spectra records to its own meter provider.
 // This is called automatically by spectra.Init().
-func (s *Spectra) initMetrics() error {
-	var initErr error
+func (s *Spectra) initMetrics(meter metric.Meter) error {
+	metrics, err := newMetrics(meter)
+	if err != nil {
+		return err
+	}
+
+	s.metrics = metrics
 
-	metricsOnce.Do(func() {
-		meter := otel.Meter("spectra")
+	return nil
+}
 
-		duration, err := meter.Float64Histogram(
-			"test.duration",
-			metric.WithDescription("Duration of test execution in seconds"),
-			metric.WithUnit("s"),
-		)
-		if err != nil {
-			initErr = fmt.Errorf("create duration histogram: %w", err)
+// Meter returns the meter spectra records test metrics with, so custom
+// instruments share its provider and resource. If metrics are disabled, a
+// no-op meter is returned.
+func (s *Spectra) Meter() metric.Meter {
+	if s == nil || s.metrics == nil {
+		return noop.NewMeterProvider().Meter("spectra")
+	}
 
-			return
-		}
+	return s.metrics.meter
+}
+
+// MetricsHandler returns the handler serving metrics for Prometheus to
+// scrape when WithPrometheusExporter is set. Otherwise it responds with 404
+// Not Found.
+func (s *Spectra) MetricsHandler() http.Handler {
+	if s == nil || s.metricsHandler == nil {
+		return http.NotFoundHandler()
+	}
+
+	return s.metricsHandler
+}
+
+// defaultDurationBuckets returns the default test.duration boundaries in
+// seconds, spanning fast unit tests to slow integration tests.
+func defaultDurationBuckets() []float64 {
+	return []float64{0.001, 0.01, 0.1, 1, 10}
+}
+
+// newMetrics creates the test metrics instruments from meter.
+func newMetrics(meter metric.Meter) (*Metrics, error) {
+	duration, err := meter.Float64Histogram(
+		metricTestDuration,
+		metric.WithDescription("Duration of test execution in seconds"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create duration histogram: %w", err)
+	}
 
-		count, err := meter.Int64Counter(
-			"test.count",
-			metric.WithDescription("Number of tests executed"),
-			metric.WithUnit("{test}"),
-		)
-		if err != nil {
-			initErr = fmt.Errorf("create count counter: %w", err)
+	count, err := meter.Int64Counter(
+		metricTestCount,
+		metric.WithDescription("Number of tests executed"),
+		metric.WithUnit("{test}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create count counter: %w", err)
+	}
+
+	failures, err := meter.Int64Counter(
+		metricTestFailures,
+		metric.WithDescription("Number of failed tests"),
+		metric.WithUnit("{test}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create failures counter: %w", err)
+	}
+
+	active, err := meter.Int64UpDownCounter(
+		metricTestActive,
+		metric.WithDescription("Number of tests currently running"),
+		metric.WithUnit("{test}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create active counter: %w", err)
+	}
+
+	return &Metrics{
+		duration: duration,
+		count:    count,
+		failures: failures,
+		active:   active,
+		meter:    meter,
+		values:   make(map[string]metric.Float64Histogram),
+		names:    make(map[string]struct{}),
+	}, nil
+}
+
+// testName returns the test.name value to record for name when at most limit
+// distinct names are allowed. Once the limit is reached, new names fall back
+// to their top-level test name if it was already recorded or still fits, and
+// to "other" otherwise. A limit of 0 records every name as is.
+func (m *Metrics) testName(name string, limit int) string {
+	if limit <= 0 {
+		return name
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-			return
+	topLevel, _, _ := strings.Cut(name, "/")
+
+	for _, candidate := range []string{name, topLevel} {
+		if _, ok := m.names[candidate]; ok {
+			return candidate
 		}
 
-		testMetrics = &Metrics{
-			duration: duration,
-			count:    count,
+		if len(m.names) < limit {
+			m.names[candidate] = struct{}{}
+
+			return candidate
 		}
-	})
+	}
 
-	return initErr
+	return otherTestName
+}
+
+// valueHistogram returns the histogram for custom values named name,
+// creating it on first use. An error, such as for an invalid instrument name,
+// is only returned on first use; the SDK still returns a usable histogram
+// along with it, which is cached like any other.
+func (m *Metrics) valueHistogram(name string) (metric.Float64Histogram, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if histogram, ok := m.values[name]; ok {
+		return histogram, nil
+	}
+
+	histogram, err := m.meter.Float64Histogram(name)
+	if histogram == nil {
+		histogram = noop.Float64Histogram{}
+	}
+
+	m.values[name] = histogram
+
+	if err != nil {
+		return histogram, fmt.Errorf("create %s histogram: %w", name, err)
+	}
+
+	return histogram, nil
+}
+
+// RecordValue records value in a histogram named name, tagged with the test
+// name and attrs. The histogram is created on first use and shared by all
+// tests. Nothing is recorded when metrics are disabled. Errors creating the
+// histogram, e.g. for a name with spaces, go to the configured Logger rather
+// than failing the test.
+//
+// Example:
+//
+//	st.RecordValue("rows.processed", float64(len(rows)))
+func (t *T) RecordValue(name string, value float64, attrs ...attribute.KeyValue) {
+	t.Helper()
+
+	if t.spectra == nil || t.spectra.metrics == nil {
+		return
+	}
+
+	histogram, err := t.spectra.metrics.valueHistogram(name)
+	if err != nil {
+		t.spectra.config.Logger("spectra: %v", err)
+	}
+
+	attrs = append([]attribute.KeyValue{attribute.String(attrTestName, t.Name())}, attrs...)
+	histogram.Record(t.Context(), value, metric.WithAttributes(attrs...))
+}
+
+// Measure runs f and times it. It adds name.start and name.end events to the
+// test span, sets the name.duration_ms attribute, and records the duration in
+// milliseconds to the name.duration_ms histogram as RecordValue does. The
+// duration is recorded even if f panics or stops the test.
+//
+// Example:
+//
+//	st.Measure("import", func() {
+//	    importFixtures(ctx)
+//	})
+func (t *T) Measure(name string, f func()) {
+	t.Helper()
+
+	t.Span().AddEvent(name + ".start")
+
+	start := time.Now()
+
+	defer func() {
+		duration := millis(time.Since(start))
+
+		t.Span().AddEvent(name + ".end")
+		t.Span().SetAttributes(attribute.Float64(name+".duration_ms", duration))
+		t.RecordValue(name+".duration_ms", duration)
+	}()
+
+	f()
+}
+
+// recordTestActive adjusts the number of running tests by delta.
+func recordTestActive(ctx context.Context, s *Spectra, delta int64) {
+	if s == nil || s.metrics == nil {
+		return
+	}
+
+	s.metrics.active.Add(ctx, delta)
 }
 
 // recordTestMetrics records metrics for a completed test.
-func recordTestMetrics(ctx context.Context, testName string, duration time.Duration, status string) {
-	if testMetrics == nil {
+func recordTestMetrics(ctx context.Context, s *Spectra, testName string, duration time.Duration, status string) {
+	if s == nil || s.metrics == nil {
 		return
 	}
 
-	attrs := []attribute.KeyValue{
-		attribute.String(attrTestName, testName),
-		attribute.String(attrTestStatus, status),
+	var nameAttrs []attribute.KeyValue
+
+	if !s.config.DisableTestNameMetricAttribute {
+		testName = s.metrics.testName(testName, s.config.MetricNameCardinalityLimit)
+		nameAttrs = []attribute.KeyValue{attribute.String(attrTestName, testName)}
 	}
 
-	testMetrics.duration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
-	testMetrics.count.Add(ctx, 1, metric.WithAttributes(attrs...))
+	attrs := append(slices.Clone(nameAttrs), attribute.String(attrTestStatus, status))
+
+	s.metrics.duration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+	s.metrics.count.Add(ctx, 1, metric.WithAttributes(attrs...))
+
+	if status == statusFail {
+		s.metrics.failures.Add(ctx, 1, metric.WithAttributes(nameAttrs...))
+	}
 }
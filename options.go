@@ -1,24 +1,72 @@
 package spectra
 
-import "time"
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+)
 
 // Option configures spectra initialization.
 type Option func(*config)
 
-// WithServiceName sets the service name for telemetry. Required.
+// Compression selects how OTLP export payloads are compressed.
+type Compression string
+
+const (
+	// CompressionNone sends export payloads uncompressed. This is the default.
+	CompressionNone Compression = ""
+
+	// CompressionGzip compresses export payloads with gzip.
+	CompressionGzip Compression = "gzip"
+)
+
+// WithServiceName sets the service name for telemetry. Required unless
+// OTEL_SERVICE_NAME is set; an explicit name takes precedence.
 func WithServiceName(name string) Option {
 	return func(c *config) {
 		c.ServiceName = name
 	}
 }
 
-// WithEndpoint sets the OTLP collector endpoint. Required.
+// WithEndpoint sets the OTLP collector endpoint. Required unless
+// OTEL_EXPORTER_OTLP_ENDPOINT is set; an explicit endpoint takes precedence.
 func WithEndpoint(endpoint string) Option {
 	return func(c *config) {
 		c.Endpoint = endpoint
 	}
 }
 
+// WithStdoutExporter pretty-prints telemetry to stderr instead of exporting
+// it to a collector. It is shorthand for WithEndpoint("stdout://").
+func WithStdoutExporter() Option {
+	return WithEndpoint("stdout://")
+}
+
+// WithTracesEndpoint sets the OTLP endpoint for traces, overriding WithEndpoint.
+func WithTracesEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.TracesEndpoint = endpoint
+	}
+}
+
+// WithMetricsEndpoint sets the OTLP endpoint for metrics, overriding WithEndpoint.
+func WithMetricsEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.MetricsEndpoint = endpoint
+	}
+}
+
 // WithInsecure disables TLS for the OTLP exporter.
 func WithInsecure() Option {
 	return func(c *config) {
@@ -26,6 +74,61 @@ func WithInsecure() Option {
 	}
 }
 
+// WithTLSConfig sets the TLS client configuration for https:// and grpc://
+// endpoints, e.g. to trust a private CA. Setting it also enables TLS for
+// http:// endpoints, such as a proxy that terminates TLS. It cannot be
+// combined with WithInsecure.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *config) {
+		c.TLSConfig = tlsConfig
+	}
+}
+
+// WithCACertFile trusts the CA certificates in the given PEM file when
+// verifying https:// and grpc:// collectors. Init returns an error if the
+// file cannot be read or contains no certificates.
+func WithCACertFile(path string) Option {
+	return func(c *config) {
+		c.CACertFile = path
+	}
+}
+
+// WithClientCertificate presents the PEM certificate and key to the collector
+// for mutual TLS. It can be combined with WithCACertFile and WithTLSConfig.
+// Init returns an error if the key pair cannot be loaded.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(c *config) {
+		c.ClientCertFile = certFile
+		c.ClientKeyFile = keyFile
+	}
+}
+
+// WithGRPCDialOptions passes opts to the gRPC connection of every exporter,
+// e.g. for keepalive, message size limits or interceptors. Init returns
+// ErrDialOptionsRequireGRPC if an enabled signal uses a non-gRPC endpoint.
+func WithGRPCDialOptions(opts ...grpc.DialOption) Option {
+	return func(c *config) {
+		c.GRPCDialOptions = append(c.GRPCDialOptions, opts...)
+	}
+}
+
+// WithHeaders sets headers sent with every export request, such as
+// authorization or tenant headers required by the collector.
+// The headers apply to both the trace and metric exporters.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *config) {
+		c.Headers = maps.Clone(headers)
+	}
+}
+
+// WithCompression sets the compression used for OTLP exports.
+// Defaults to CompressionNone.
+func WithCompression(c Compression) Option {
+	return func(cfg *config) {
+		cfg.Compression = c
+	}
+}
+
 // WithShutdownTimeout sets the timeout for graceful shutdown.
 // Defaults to 5 seconds if not specified.
 func WithShutdownTimeout(d time.Duration) Option {
@@ -34,6 +137,358 @@ func WithShutdownTimeout(d time.Duration) Option {
 	}
 }
 
+// WithExportTimeout sets the timeout for each export request to the collector.
+// It is independent of the shutdown timeout. Defaults to 10 seconds if not specified.
+func WithExportTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.ExportTimeout = d
+	}
+}
+
+// WithRetryConfig sets the backoff used by every exporter to retry failed
+// exports: the first retry waits initialInterval, waits grow up to
+// maxInterval, and a batch is dropped after maxElapsed. Defaults to the OTLP
+// exporter defaults.
+func WithRetryConfig(initialInterval, maxInterval, maxElapsed time.Duration) Option {
+	return func(c *config) {
+		c.Retry = &retryConfig{
+			InitialInterval: initialInterval,
+			MaxInterval:     maxInterval,
+			MaxElapsedTime:  maxElapsed,
+		}
+	}
+}
+
+// WithDurationBuckets sets the explicit histogram boundaries, in seconds, used
+// for test.duration. Defaults to 0.001, 0.01, 0.1, 1 and 10.
+func WithDurationBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.DurationBuckets = slices.Clone(buckets)
+	}
+}
+
+// WithMetricTemporality sets the temporality of metrics exported over OTLP,
+// e.g. metricdata.DeltaTemporality for backends that expect deltas. Up-down
+// counters such as test.active stay cumulative. Defaults to cumulative.
+func WithMetricTemporality(temporality metricdata.Temporality) Option {
+	return func(c *config) {
+		c.MetricTemporality = temporality
+	}
+}
+
+// WithMetricViews customizes the test metrics with views, e.g. to rename an
+// instrument, drop the test.name attribute to reduce cardinality, or change
+// an aggregation. Init returns ErrMetricViewsWithoutMetrics if metrics are
+// disabled.
+func WithMetricViews(views ...metric.View) Option {
+	return func(c *config) {
+		c.MetricViews = append(c.MetricViews, views...)
+	}
+}
+
+// WithMetricNameCardinalityLimit caps the distinct test.name values on test
+// metrics at n, for table-driven tests with many subtests. Once n names have
+// been recorded, further tests are recorded under their top-level test name
+// if it was already recorded, or under "other". Spans keep the full name.
+func WithMetricNameCardinalityLimit(n int) Option {
+	return func(c *config) {
+		c.MetricNameCardinalityLimit = n
+	}
+}
+
+// WithSampler sets the sampler used to decide which spans are exported.
+// Defaults to sdktrace.AlwaysSample() if not specified.
+func WithSampler(sampler sdktrace.Sampler) Option {
+	return func(c *config) {
+		c.Sampler = sampler
+	}
+}
+
+// WithSamplingRatio samples the given fraction of traces, between 0 and 1.
+// Spans with a sampled parent are always sampled, so subtests follow their parent test.
+func WithSamplingRatio(ratio float64) Option {
+	return WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)))
+}
+
+// WithDefaultAttributes sets attrs on every test, subtest, setup and teardown
+// span, e.g. test.suite. It may be given more than once. A test can override
+// them with SetAttributes.
+func WithDefaultAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *config) {
+		c.DefaultAttributes = append(c.DefaultAttributes, attrs...)
+	}
+}
+
+// WithResource uses res for all signals instead of the resource spectra
+// builds, e.g. one with cloud or container detectors applied. If res has no
+// service.name, the configured service name is added.
+func WithResource(res *resource.Resource) Option {
+	return func(c *config) {
+		c.Resource = res
+	}
+}
+
+// WithProcessRuntimeDetectors adds the process and container resource
+// detectors, so telemetry carries attributes such as process.pid,
+// process.runtime.version and container.id. They are off by default because
+// detection reads from /proc and the cgroup files at Init. Detectors that fail
+// are skipped.
+func WithProcessRuntimeDetectors() Option {
+	return func(c *config) {
+		c.ProcessRuntimeDetectors = true
+	}
+}
+
+// WithSpanNamePrefix prepends prefix to every test, subtest, setup and
+// teardown span name, so spans become "prefix/TestName". Use it to tell apart
+// same-named tests from different packages in a monorepo.
+func WithSpanNamePrefix(prefix string) Option {
+	return func(c *config) {
+		c.SpanNamePrefix = prefix
+	}
+}
+
+// WithSpanNamer computes test and subtest span names with namer, e.g. to
+// name spans by a ticket id parsed from the test name. The test.name
+// attribute keeps the raw test name, and WithSpanNamePrefix is applied to
+// the result.
+func WithSpanNamer(namer func(testName string) string) Option {
+	return func(c *config) {
+		c.SpanNamer = namer
+	}
+}
+
+// WithContextAttributeExtractor sets the attributes extract returns on each
+// test span and on spans started with StartSpan or StartSpanFrom, computed
+// from the context the span is started in, e.g. to lift request ids stored by
+// test helpers.
+func WithContextAttributeExtractor(extract func(ctx context.Context) []attribute.KeyValue) Option {
+	return func(c *config) {
+		c.ContextAttributeExtractor = extract
+	}
+}
+
+// WithSpanLimits bounds what each span keeps, e.g. AttributeCountLimit for
+// tests that set attributes in a loop. Excess attributes, events and links are
+// dropped. Defaults to the SDK limits.
+func WithSpanLimits(limits sdktrace.SpanLimits) Option {
+	return func(c *config) {
+		c.SpanLimits = &limits
+	}
+}
+
+// WithMetricReader collects metrics with reader instead of exporting them to
+// the metrics endpoint, e.g. a ManualReader for deterministic assertions or a
+// Prometheus exporter. It cannot be combined with WithMetricsEndpoint.
+func WithMetricReader(reader metric.Reader) Option {
+	return func(c *config) {
+		c.MetricReader = reader
+	}
+}
+
+// WithPrometheusExporter exposes metrics for Prometheus to scrape through
+// Spectra.MetricsHandler instead of pushing them over OTLP. The metrics
+// endpoint is ignored.
+func WithPrometheusExporter() Option {
+	return func(c *config) {
+		c.PrometheusExporter = true
+	}
+}
+
+// WithJSONSummary writes a JSON array with the name, status, duration and
+// trace id of every test and subtest to w on Shutdown, e.g. as a CI artifact.
+// Like WithJUnitReport, it lists every test regardless of sampling or export,
+// and works without a collector. The trace id is empty when traces are
+// disabled.
+func WithJSONSummary(w io.Writer) Option {
+	return func(c *config) {
+		c.JSONSummary = w
+	}
+}
+
+// WithSpanExporter sends spans to exporter, batched, instead of an OTLP
+// exporter, e.g. an in-memory exporter in tests or a Zipkin exporter.
+// No traces endpoint is required.
+func WithSpanExporter(exporter sdktrace.SpanExporter) Option {
+	return func(c *config) {
+		c.SpanExporter = exporter
+	}
+}
+
+// WithSpanProcessor registers processor on the tracer provider, e.g. for
+// tail sampling or attribute scrubbing. It may be given more than once.
+// Processors run in the order given, before the batching exporter. Every span
+// they see start also ends, including the "TestName/parallel" span a subtest
+// ends when it calls Parallel.
+func WithSpanProcessor(processor sdktrace.SpanProcessor) Option {
+	return func(c *config) {
+		c.SpanProcessors = append(c.SpanProcessors, processor)
+	}
+}
+
+// WithPropagator sets the global text map propagator installed by Init,
+// e.g. a B3 propagator for services that use B3 headers.
+// Defaults to W3C trace context and baggage.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.Propagator = propagator
+	}
+}
+
+// WithTestEndHook calls hook with the name, status, duration and trace id of
+// each test created by New once its span has ended, e.g. to write a JUnit
+// line per test. Subtests are not reported.
+func WithTestEndHook(hook func(TestResult)) Option {
+	return func(c *config) {
+		c.TestEndHook = hook
+	}
+}
+
+// WithJUnitReport writes a JUnit XML report of every test and subtest to w on
+// Shutdown, for CI systems that ingest JUnit. Failures carry the messages
+// logged through Error and Fatal; subtests use their parent test as
+// classname.
+func WithJUnitReport(w io.Writer) Option {
+	return func(c *config) {
+		c.JUnitReport = w
+	}
+}
+
+// WithGlobalProviders controls whether Init installs its tracer, meter and
+// logger providers, propagator and error handler as the otel globals.
+// Defaults to true. Pass false to keep them on the Spectra instance, e.g. to
+// run two instances side by side; code under test that uses the otel globals
+// then does not report to spectra, and WithLogger receives no SDK errors.
+func WithGlobalProviders(enabled bool) Option {
+	return func(c *config) {
+		c.DisableGlobalProviders = !enabled
+	}
+}
+
+// WithAttributesFromEnv attaches every environment variable named with prefix
+// to each test span. Keys are lowercased with the prefix stripped and
+// underscores replaced by dots, so CI_JOB_ID with prefix "CI_" becomes
+// job.id. The environment is read once by Init.
+func WithAttributesFromEnv(prefix string) Option {
+	return func(c *config) {
+		c.AttributesEnvPrefix = prefix
+	}
+}
+
+// WithCIContextFromEnv nests test spans under the trace a CI system exported
+// in the TRACEPARENT and TRACESTATE environment variables, so a whole suite
+// run shows up inside the pipeline's trace. The variables are read once by
+// Init and extracted with the configured propagator.
+func WithCIContextFromEnv() Option {
+	return func(c *config) {
+		c.CIContextFromEnv = true
+	}
+}
+
+// WithLogger routes spectra's internal diagnostics, such as export errors, to
+// logger instead of log.Printf. Pass a no-op function to silence them.
+func WithLogger(logger func(format string, args ...any)) Option {
+	return func(c *config) {
+		c.Logger = logger
+	}
+}
+
+// WithLogScrubber rewrites every captured log message with scrubber before it
+// is recorded as a span event or log record, e.g. to redact tokens.
+//
+// Example:
+//
+//	token := regexp.MustCompile(`Bearer \S+`)
+//	spectra.WithLogScrubber(func(message string) string {
+//	    return token.ReplaceAllString(message, "Bearer [REDACTED]")
+//	})
+func WithLogScrubber(scrubber func(message string) string) Option {
+	return func(c *config) {
+		c.LogScrubber = scrubber
+	}
+}
+
+// WithLogExporter emits test logs as OTLP log records to the configured
+// endpoint, in addition to recording them as span events.
+func WithLogExporter() Option {
+	return func(c *config) {
+		c.ExportLogs = true
+	}
+}
+
+// WithDisabled turns spectra into a no-op for runs without a collector, e.g.
+// local unit tests. Init builds no exporters and ignores every other option;
+// New and all T methods keep working but record nothing.
+func WithDisabled() Option {
+	return func(c *config) {
+		c.Disabled = true
+	}
+}
+
+// WithDebugLogs records T.Debugf messages as debug-level span events. Without
+// it, Debugf only writes to the test log.
+func WithDebugLogs() Option {
+	return func(c *config) {
+		c.DebugLogs = true
+	}
+}
+
+// WithCleanupTracing records a cleanup.registered event on the test span
+// when a function is registered with T.Cleanup, and a cleanup.run event when
+// it runs, for debugging cleanup ordering. Both carry a cleanup.index
+// attribute with the registration order.
+func WithCleanupTracing() Option {
+	return func(c *config) {
+		c.CleanupTracing = true
+	}
+}
+
+// WithCodeLocation sets the code.filepath and code.lineno attributes on each
+// test span to the file and line where New was called, for navigating from a
+// trace back to the test.
+func WithCodeLocation() Option {
+	return func(c *config) {
+		c.CodeLocation = true
+	}
+}
+
+// WithMemoryProfiling sets the test.alloc_bytes attribute on each test span
+// to the bytes allocated between New and the end of the test, to catch tests
+// with pathological allocation. The count is process-wide, so it includes
+// allocations of tests running in parallel. Reading it briefly stops the
+// world at the start and end of every test.
+func WithMemoryProfiling() Option {
+	return func(c *config) {
+		c.MemoryProfiling = true
+	}
+}
+
+// WithGoroutineLeakCheck compares the number of goroutines when each test
+// ends with the number when New was called. If goroutines are left over after
+// a short settle delay, a test.goroutine_leak event is recorded on the test
+// span, and with failOnLeak the test fails. Cleanups registered after New run
+// first, so goroutines they stop are not reported. The count is process-wide,
+// so parallel tests can cause false positives.
+func WithGoroutineLeakCheck(failOnLeak bool) Option {
+	return func(c *config) {
+		c.GoroutineLeakCheck = true
+		c.GoroutineLeakFail = failOnLeak
+	}
+}
+
+// WithStdLogCapture records lines written through the standard library log
+// package while a test runs as log events on its span, for code that doesn't
+// log through T yet. The lines still reach the log output once. With
+// parallel tests, a line is attributed to the most recently started test.
+// Output of testing.T's Log cannot be captured, as the testing package offers
+// no hook for it; log through T instead to record those lines. A LogScrubber
+// must not log through the log package while capture is enabled.
+func WithStdLogCapture() Option {
+	return func(c *config) {
+		c.StdLogCapture = true
+	}
+}
+
 // WithoutTraces disables trace collection.
 func WithoutTraces() Option {
 	return func(c *config) {
@@ -48,6 +503,15 @@ func WithoutMetrics() Option {
 	}
 }
 
+// WithoutTestNameMetricAttribute omits the test.name attribute from the
+// test.duration, test.count and test.failures metrics, so they are only
+// broken down by status. Spans keep test.name.
+func WithoutTestNameMetricAttribute() Option {
+	return func(c *config) {
+		c.DisableTestNameMetricAttribute = true
+	}
+}
+
 // WithoutLogs disables log capture as span events.
 func WithoutLogs() Option {
 	return func(c *config) {
@@ -0,0 +1,255 @@
+package spectra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	errAttemptFailed  = errors.New("attempt failed")
+	errAttemptSkipped = errors.New("attempt skipped")
+)
+
+// Retry runs f up to maxAttempts times until it returns nil, each attempt in
+// a child span named "TestName/attempt-N" with the attempt number and its
+// outcome in test.attempt.status. If f only succeeds after a retry, the test
+// span gets a test.flaky=true attribute. If every attempt fails, the test is
+// marked failed with the last error. A maxAttempts below 1 runs f once.
+// Retry reports whether an attempt succeeded.
+//
+// Each attempt gets its own T: Error, Fatal and the assertions fail only that
+// attempt, Fatal and FailNow end it, and its cleanups run when it ends. The
+// messages are still written to the test log. Skipping within an attempt
+// skips the test.
+//
+// Example:
+//
+//	st.Retry(3, func(st *spectra.T) error {
+//	    return pingService(st.Context())
+//	})
+func (t *T) Retry(maxAttempts int, f func(*T) error) bool {
+	t.Helper()
+
+	maxAttempts = max(maxAttempts, 1)
+
+	var err error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = t.runAttempt(attempt, f)
+		if errors.Is(err, errAttemptSkipped) {
+			t.SkipNow()
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if attempt > 1 {
+			t.Span().SetAttributes(attribute.Bool(attrTestFlaky, true))
+		}
+
+		return true
+	}
+
+	t.Errorf("spectra: failed after %d attempts: %v", maxAttempts, err)
+
+	return false
+}
+
+// runAttempt runs f as attempt number attempt within its own span. f runs on
+// its own goroutine with an attemptTB, so Fatal and FailNow only end the
+// attempt.
+func (t *T) runAttempt(attempt int, f func(*T) error) error {
+	ctx, span := t.tracer.Start(
+		t.Context(),
+		t.spectra.spanName(fmt.Sprintf("%s/attempt-%d", t.Name(), attempt)),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(t.spectra.defaultAttributes()...),
+		trace.WithAttributes(
+			attribute.Int(attrTestAttempt, attempt),
+		),
+	)
+	defer span.End()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tb := &attemptTB{TB: t.TB}
+
+	var (
+		err       error
+		recovered any
+	)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer tb.runCleanups()
+		defer func() { recovered = recover() }()
+
+		err = f(&T{
+			TB:        tb,
+			ctx:       ctx,
+			cancel:    cancel,
+			span:      span,
+			tracer:    t.tracer,
+			spectra:   t.spectra,
+			parent:    t,
+			startTime: time.Now(),
+		})
+	}()
+
+	<-done
+
+	if recovered != nil {
+		panic(recovered)
+	}
+
+	if err == nil {
+		err = tb.err()
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "attempt failed")
+		span.SetAttributes(attribute.String(attrAttemptStatus, statusFail))
+
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "attempt passed")
+	span.SetAttributes(attribute.String(attrAttemptStatus, statusPass))
+
+	return nil
+}
+
+// attemptTB is the testing.TB of a single Retry attempt. Failures and skips
+// are recorded for the attempt instead of the test, messages are written to
+// the test log, and cleanups run when the attempt ends.
+type attemptTB struct {
+	testing.TB
+
+	mu       sync.Mutex
+	failed   bool
+	skipped  bool
+	messages []string
+	cleanups []func()
+}
+
+func (a *attemptTB) Cleanup(f func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.cleanups = append(a.cleanups, f)
+}
+
+func (a *attemptTB) Error(args ...any) {
+	a.TB.Log(args...)
+	a.fail(formatArgs(args...))
+}
+
+func (a *attemptTB) Errorf(format string, args ...any) {
+	a.TB.Logf(format, args...)
+	a.fail(formatf(format, args...))
+}
+
+func (a *attemptTB) Fail() {
+	a.fail("")
+}
+
+func (a *attemptTB) Failed() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.failed
+}
+
+func (a *attemptTB) FailNow() {
+	a.fail("")
+	runtime.Goexit()
+}
+
+func (a *attemptTB) Fatal(args ...any) {
+	a.Error(args...)
+	runtime.Goexit()
+}
+
+func (a *attemptTB) Fatalf(format string, args ...any) {
+	a.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+func (a *attemptTB) Skip(args ...any) {
+	a.TB.Log(args...)
+	a.SkipNow()
+}
+
+func (a *attemptTB) Skipf(format string, args ...any) {
+	a.TB.Logf(format, args...)
+	a.SkipNow()
+}
+
+func (a *attemptTB) SkipNow() {
+	a.mu.Lock()
+	a.skipped = true
+	a.mu.Unlock()
+
+	runtime.Goexit()
+}
+
+func (a *attemptTB) Skipped() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.skipped
+}
+
+func (a *attemptTB) fail(message string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.failed = true
+
+	if message != "" {
+		a.messages = append(a.messages, message)
+	}
+}
+
+// err returns the attempt's outcome, or nil if it neither failed nor skipped.
+func (a *attemptTB) err() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch {
+	case a.skipped:
+		return errAttemptSkipped
+	case a.failed && len(a.messages) > 0:
+		return fmt.Errorf("%w: %s", errAttemptFailed, strings.Join(a.messages, "; "))
+	case a.failed:
+		return errAttemptFailed
+	default:
+		return nil
+	}
+}
+
+func (a *attemptTB) runCleanups() {
+	a.mu.Lock()
+	cleanups := a.cleanups
+	a.cleanups = nil
+	a.mu.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+}
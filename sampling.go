@@ -0,0 +1,70 @@
+package spectra
+
+import (
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// forceSampler samples spans started with the sampling.force attribute and
+// defers to the configured sampler for all others.
+type forceSampler struct {
+	sdktrace.Sampler
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s forceSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, attr := range p.Attributes {
+		if attr.Key == attrSamplingForce && attr.Value.AsBool() {
+			return sdktrace.SamplingResult{
+				Decision:   sdktrace.RecordAndSample,
+				Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+			}
+		}
+	}
+
+	return s.Sampler.ShouldSample(p)
+}
+
+// Description implements sdktrace.Sampler.
+func (s forceSampler) Description() string {
+	return "ForceSample{" + s.Sampler.Description() + "}"
+}
+
+// ForceSample keeps the test span regardless of the configured sampler, e.g.
+// for a test that is about to fail. If the span was not sampled, it is
+// restarted with the same name, parent and start time, so anything recorded
+// on it earlier is lost. The unsampled span is ended, so span processors see
+// it end, but it is never exported. Spans already started from the test, such as setup
+// spans or subtests, keep their sampling decision; spans started afterwards
+// are sampled if the sampler is parent-based, as with WithSamplingRatio.
+//
+// Sampling is decided when a span starts, so ForceSample cannot keep a span
+// after the fact. To keep every failing test, sample all spans and filter
+// them in a collector with tail sampling instead.
+func (t *T) ForceSample() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.span.SpanContext().IsSampled() {
+		t.span.SetAttributes(attribute.Bool(attrSamplingForce, true))
+
+		return
+	}
+
+	opts := append(slices.Clone(t.spanOptions), trace.WithAttributes(attribute.Bool(attrSamplingForce, true)))
+
+	//nolint:spancheck // Ended by the test cleanup through t.span.
+	ctx, span := t.tracer.Start(
+		trace.ContextWithSpanContext(t.ctx, t.spanParent),
+		t.spectra.testSpanName(t.Name()),
+		opts...,
+	)
+
+	t.span.End()
+
+	t.ctx = ctx
+	t.span = span
+}
@@ -2,8 +2,11 @@ package spectra
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -21,11 +24,37 @@ import (
 //
 //nolint:spancheck // Caller is responsible for ending the span.
 func (t *T) StartSpan(name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
-	return t.tracer.Start(t.ctx, name, opts...)
+	return t.StartSpanFrom(t.Context(), name, opts...)
+}
+
+// StartSpanFrom creates a span like StartSpan, but as a child of the span in
+// ctx rather than the test span, e.g. from the context passed to a Step.
+// The caller is responsible for ending the span with span.End().
+//
+// Example:
+//
+//	st.Step("act", func(ctx context.Context) {
+//	    ctx, span := st.StartSpanFrom(ctx, "checkout")
+//	    defer span.End()
+//	    checkout(ctx)
+//	})
+//
+//nolint:spancheck // Caller is responsible for ending the span.
+func (t *T) StartSpanFrom(
+	ctx context.Context,
+	name string,
+	opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	if attrs := t.spectra.contextAttributes(ctx); len(attrs) > 0 {
+		opts = append([]trace.SpanStartOption{trace.WithAttributes(attrs...)}, opts...)
+	}
+
+	return t.tracer.Start(ctx, name, opts...)
 }
 
 // Setup runs a setup function within a traced span.
-// The setup span is automatically ended when the function returns.
+// The setup span is automatically ended when the function returns, with its
+// duration recorded in the phase.duration_ms attribute.
 //
 // Example:
 //
@@ -39,19 +68,30 @@ func (t *T) Setup(fn func(ctx context.Context)) {
 	t.Helper()
 
 	ctx, span := t.tracer.Start(
-		t.ctx,
-		t.Name()+spanSetup,
+		t.Context(),
+		t.spectra.spanName(t.Name()+spanSetup),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(t.spectra.defaultAttributes()...),
 		trace.WithAttributes(
 			attribute.String(attrTestPhase, "setup"),
 		),
 	)
-	defer span.End()
+
+	start := time.Now()
+
+	defer func() {
+		span.SetAttributes(attribute.Float64(attrPhaseDuration, millis(time.Since(start))))
+		span.End()
+	}()
 
 	fn(ctx)
 }
 
 // Teardown registers a teardown function that runs within a traced span.
 // The teardown is registered via t.Cleanup and runs after the test completes.
+// Its duration is recorded in the phase.duration_ms attribute of the span.
+// fn gets a context that is not canceled when the test fails with Fatal or
+// FailNow, so cleanup such as server.Shutdown(ctx) still runs.
 //
 // Example:
 //
@@ -66,14 +106,105 @@ func (t *T) Teardown(fn func(ctx context.Context)) {
 
 	t.Cleanup(func() {
 		ctx, span := t.tracer.Start(
-			t.ctx,
-			t.Name()+spanTeardown,
+			context.WithoutCancel(t.Context()),
+			t.spectra.spanName(t.Name()+spanTeardown),
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(t.spectra.defaultAttributes()...),
 			trace.WithAttributes(
 				attribute.String(attrTestPhase, "teardown"),
 			),
 		)
+
+		start := time.Now()
+
+		defer func() {
+			span.SetAttributes(attribute.Float64(attrPhaseDuration, millis(time.Since(start))))
+			span.End()
+		}()
+
+		fn(ctx)
+	})
+}
+
+// TracedCleanup registers a named cleanup function that runs within a traced
+// span named "TestName/cleanup/name". Unlike Teardown, it can be called
+// multiple times to trace several cleanups separately. Like Teardown, fn gets
+// a context that is not canceled when the test fails with Fatal or FailNow.
+//
+// Example:
+//
+//	func TestWithServer(t *testing.T) {
+//	    st := spectra.New(t)
+//	    st.TracedCleanup("stop-server", func(ctx context.Context) {
+//	        server.Shutdown(ctx)
+//	    })
+//	}
+func (t *T) TracedCleanup(name string, fn func(ctx context.Context)) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		ctx, span := t.tracer.Start(
+			context.WithoutCancel(t.Context()),
+			t.spectra.spanName(t.Name()+spanCleanup+name),
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(t.spectra.defaultAttributes()...),
+			trace.WithAttributes(
+				attribute.String(attrTestPhase, "cleanup"),
+			),
+		)
 		defer span.End()
 
 		fn(ctx)
 	})
 }
+
+// Step runs fn within a child span named "TestName/name", for sequential
+// phases such as arrange, act and assert. Each step starts from the current
+// test context, so baggage set with SetBaggage in an earlier step is visible
+// to later ones. If fn panics, the span is marked as failed before the panic
+// is propagated.
+//
+// Example:
+//
+//	func TestCheckout(t *testing.T) {
+//	    st := spectra.New(t)
+//	    st.Step("arrange", func(ctx context.Context) {
+//	        seedCart(ctx)
+//	    })
+//	    st.Step("act", func(ctx context.Context) {
+//	        checkout(ctx)
+//	    })
+//	}
+func (t *T) Step(name string, fn func(ctx context.Context)) {
+	t.Helper()
+
+	ctx, span := t.tracer.Start(
+		t.Context(),
+		t.spectra.spanName(t.Name()+"/"+name),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(t.spectra.defaultAttributes()...),
+		trace.WithAttributes(
+			attribute.String(attrTestPhase, "step"),
+		),
+	)
+	defer span.End()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		err, ok := r.(error)
+		if !ok {
+			err = fmt.Errorf("%w: %v", errPanic, r)
+		}
+
+		span.RecordError(err, trace.WithStackTrace(true))
+		span.SetStatus(codes.Error, "step panicked")
+
+		panic(r)
+	}()
+
+	fn(ctx)
+}
@@ -5,7 +5,13 @@ package spectra
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -13,25 +19,78 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 const (
 	// Event names.
-	logEventName = "log"
+	logEventName       = "log"
+	assertionEventName = "assertion"
+	setenvEventName    = "setenv"
+	timeoutEventName   = "timeout"
+	leakEventName      = "test.goroutine_leak"
+
+	cleanupRegisteredEventName = "cleanup.registered"
+	cleanupRunEventName        = "cleanup.run"
 
 	// Attribute keys.
-	attrMessage    = "message"
-	attrLevel      = "level"
-	attrTestName   = "test.name"
-	attrTestPhase  = "test.phase"
-	attrTestParent = "test.parent"
-	attrTestStatus = "test.status"
+	attrMessage        = "message"
+	attrLevel          = "level"
+	attrSeverityNumber = "severity.number"
+	attrTestName       = "test.name"
+	attrTestPhase      = "test.phase"
+	attrTestParent     = "test.parent"
+	attrTestStatus     = "test.status"
+	attrTestDeadline   = "test.deadline"
+	attrTestTempDir    = "test.tempdir"
+	attrTestFramework  = "test.framework"
+	attrTestTimeout    = "test.timeout"
+	attrTestDuration   = "test.duration_ms"
+	attrTestAllocBytes = "test.alloc_bytes"
+	attrTestAttempt    = "test.attempt"
+	attrAttemptStatus  = "test.attempt.status"
+	attrTestFlaky      = "test.flaky"
+	attrTestCase       = "test.case"
+	attrTestCaseIndex  = "test.case_index"
+
+	attrSamplingForce = "sampling.force"
+
+	attrGoroutinesBefore = "goroutines.before"
+	attrGoroutinesAfter  = "goroutines.after"
+
+	attrHostNumCPU = "host.num_cpu"
+	attrGoMaxProcs = "go.max_procs"
+
+	attrPhaseDuration = "phase.duration_ms"
+
+	attrCleanupIndex = "cleanup.index"
+
+	attrBenchmarkN           = "benchmark.n"
+	attrBenchmarkNsPerOp     = "benchmark.ns_per_op"
+	attrBenchmarkAllocsPerOp = "benchmark.allocs_per_op"
+	attrBenchmarkBytesPerOp  = "benchmark.bytes_per_op"
+	attrBenchmarkMetric      = "benchmark.metric."
+
+	attrFuzzInputBytes = "fuzz.input.bytes"
+
+	attrEnvKey = "env.key"
+
+	attrAssertionName   = "assertion.name"
+	attrAssertionPassed = "assertion.passed"
+	attrAssertionGot    = "assertion.got"
+	attrAssertionWant   = "assertion.want"
+	attrAssertionError  = "assertion.error"
 
 	// Log levels.
+	levelDebug = "debug"
 	levelInfo  = "info"
+	levelWarn  = "warn"
 	levelError = "error"
 	levelFatal = "fatal"
 	levelSkip  = "skip"
@@ -39,6 +98,8 @@ const (
 	// Span name suffixes.
 	spanSetup    = "/setup"
 	spanTeardown = "/teardown"
+	spanCleanup  = "/cleanup/"
+	spanParallel = "/parallel"
 
 	// Status strings.
 	statusPass = "pass"
@@ -46,162 +107,669 @@ const (
 	statusSkip = "skip"
 )
 
+var errPanic = errors.New("panic")
+
 type Spectra struct {
 	config         config
 	tracerProvider *sdktrace.TracerProvider
 	meterProvider  *metric.MeterProvider
+	loggerProvider *sdklog.LoggerProvider
+	metrics        *Metrics
+	metricsHandler http.Handler
 	tracer         trace.Tracer
+	logger         otellog.Logger
+	parentCtx      context.Context //nolint:containedctx // Default parent for test spans, set once by Init.
+	envAttributes  []attribute.KeyValue
+	suiteCtx       context.Context //nolint:containedctx // Parent for test spans while a suite is active.
+	suiteSpan      trace.Span
+	junit          *junitReport
+	summary        *jsonSummary
+	logCapture     logCapture
 	shutdownOnce   sync.Once
 	initialized    bool
 	shutdown       bool
 	mu             sync.RWMutex
 }
 
-func (s *Spectra) Shutdown() {
+// Shutdown flushes and stops the tracer, meter and logger providers and writes
+// the JUnit report, if any. Their errors are joined. Only the first call does
+// any work; later calls return nil. It is bounded by the shutdown timeout; use
+// ShutdownContext to cancel it earlier.
+func (s *Spectra) Shutdown() error {
+	return s.ShutdownContext(context.Background())
+}
+
+// ShutdownContext is like Shutdown but gives up once ctx is done, e.g. on a
+// signal handled in TestMain. It is still bounded by the shutdown timeout.
+func (s *Spectra) ShutdownContext(ctx context.Context) error {
+	var err error
+
 	s.shutdownOnce.Do(func() {
+		s.EndSuite()
+
 		s.mu.Lock()
 		s.shutdown = true
 		s.mu.Unlock()
 
-		ctx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+		ctx, cancel := context.WithTimeout(ctx, s.config.ShutdownTimeout)
 		defer cancel()
 
+		var errs []error
+
 		if s.tracerProvider != nil {
-			err := s.tracerProvider.Shutdown(ctx)
-			if err != nil {
-				log.Printf("spectra: failed to shutdown tracer provider: %v", err)
+			shutdownErr := s.tracerProvider.Shutdown(ctx)
+			if shutdownErr != nil {
+				errs = append(errs, fmt.Errorf("shutdown tracer provider: %w", shutdownErr))
 			}
 		}
 
 		if s.meterProvider != nil {
-			err := s.meterProvider.Shutdown(ctx)
-			if err != nil {
-				log.Printf("spectra: failed to shutdown meter provider: %v", err)
+			shutdownErr := s.meterProvider.Shutdown(ctx)
+			if shutdownErr != nil {
+				errs = append(errs, fmt.Errorf("shutdown meter provider: %w", shutdownErr))
+			}
+		}
+
+		if s.loggerProvider != nil {
+			shutdownErr := s.loggerProvider.Shutdown(ctx)
+			if shutdownErr != nil {
+				errs = append(errs, fmt.Errorf("shutdown logger provider: %w", shutdownErr))
 			}
 		}
+
+		if s.junit != nil {
+			writeErr := s.junit.write()
+			if writeErr != nil {
+				errs = append(errs, writeErr)
+			}
+		}
+
+		if s.summary != nil {
+			writeErr := s.summary.write()
+			if writeErr != nil {
+				errs = append(errs, writeErr)
+			}
+		}
+
+		err = errors.Join(errs...)
 	})
+
+	return err
+}
+
+// Flush exports all telemetry recorded so far without shutting down, so a
+// backend can be queried while tests are still running.
+func (s *Spectra) Flush(ctx context.Context) error {
+	if s == nil || !s.initialized {
+		return ErrNotInitialized
+	}
+
+	s.mu.RLock()
+	shutdown := s.shutdown
+	s.mu.RUnlock()
+
+	if shutdown {
+		return ErrAlreadyShutdown
+	}
+
+	if s.tracerProvider != nil {
+		err := s.tracerProvider.ForceFlush(ctx)
+		if err != nil {
+			return fmt.Errorf("flush tracer provider: %w", err)
+		}
+	}
+
+	if s.meterProvider != nil {
+		err := s.meterProvider.ForceFlush(ctx)
+		if err != nil {
+			return fmt.Errorf("flush meter provider: %w", err)
+		}
+	}
+
+	if s.loggerProvider != nil {
+		err := s.loggerProvider.ForceFlush(ctx)
+		if err != nil {
+			return fmt.Errorf("flush logger provider: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Tracer returns the tracer spectra creates test spans with, for use outside a
+// T such as in package-level setup helpers. If traces are disabled or s has
+// been shut down, a no-op tracer is returned.
+func (s *Spectra) Tracer() trace.Tracer {
+	if s == nil || s.tracer == nil {
+		return noop.NewTracerProvider().Tracer("spectra")
+	}
+
+	s.mu.RLock()
+	shutdown := s.shutdown
+	s.mu.RUnlock()
+
+	if shutdown {
+		return noop.NewTracerProvider().Tracer("spectra")
+	}
+
+	return s.tracer
 }
 
 // T wraps testing.TB with OpenTelemetry instrumentation.
 // It creates spans for test execution, captures logs, and records metrics.
+// Methods T does not instrument are inherited from the embedded testing.TB,
+// so *T can be used wherever a testing.TB is expected.
 type T struct {
-	tb      testing.TB
+	testing.TB
+
 	ctx     context.Context //nolint:containedctx // Context is needed for span propagation in tests.
+	cancel  context.CancelFunc
 	span    trace.Span
 	tracer  trace.Tracer
 	spectra *Spectra
+	parent  *T
+
+	// spanParent and spanOptions are what span was started with, so
+	// ForceSample can restart it.
+	spanParent  trace.SpanContext
+	spanOptions []trace.SpanStartOption
 
 	mu        sync.Mutex
 	failed    bool
+	errors    []string
+	cleanups  int
 	startTime time.Time
 }
 
-func determineSubtestStatus(tb testing.TB) (codes.Code, string) {
+// TestResult describes a completed test, as passed to the WithTestEndHook
+// callback.
+type TestResult struct {
+	// Name is the test name.
+	Name string
+
+	// Parent is the name of the parent test for subtests, or empty.
+	Parent string
+
+	// Status is "pass", "fail" or "skip".
+	Status string
+
+	// Duration is how long the test ran.
+	Duration time.Duration
+
+	// TraceID is the hex trace id of the test span, or empty when traces are
+	// disabled.
+	TraceID string
+
+	// Failed reports whether the test failed.
+	Failed bool
+
+	// Errors are the messages the test logged through Error and Fatal.
+	Errors []string
+}
+
+// determineSubtestStatus returns the span status and metric status for a
+// completed subtest. Passing the subtest's *T also accounts for failures
+// recorded through spectra that tb has not reported yet.
+func determineSubtestStatus(tb testing.TB) (codes.Code, string, string) {
 	tb.Helper()
 
 	switch {
 	case tb.Failed():
-		return codes.Error, "subtest failed"
+		return codes.Error, "subtest failed", statusFail
 	case tb.Skipped():
-		return codes.Ok, "subtest skipped"
+		return codes.Ok, "subtest skipped", statusSkip
 	default:
-		return codes.Ok, "subtest passed"
+		return codes.Ok, "subtest passed", statusPass
 	}
 }
 
 // New creates a new instrumented test wrapper.
 // It creates a span for the test and sets up cleanup to end the span
-// with the appropriate status when the test completes.
+// with the appropriate status when the test completes. With
+// WithCIContextFromEnv, the span is a child of the CI trace.
 func (s *Spectra) New(tb testing.TB) (*T, error) {
 	tb.Helper()
 
-	if s == nil || !s.initialized {
-		return nil, ErrNotInitialized
+	return s.NewWithContext(s.parentContext(), tb)
+}
+
+// BeginSuite starts a span named name that parents every test created by New
+// until EndSuite is called, so the whole suite shows up as one trace. Call it
+// in TestMain before m.Run. The returned context carries the suite span.
+// Calling BeginSuite again while a suite is active returns the active suite's
+// context. If s is not initialized or already shut down, no suite is started.
+//
+// Example:
+//
+//	sp.BeginSuite("my-service-tests")
+//	code := m.Run()
+//	sp.EndSuite()
+func (s *Spectra) BeginSuite(name string) context.Context {
+	parent := s.parentContext()
+
+	tracer, err := s.testTracer()
+	if err != nil {
+		return parent
 	}
 
-	s.mu.RLock()
-	shutdown := s.shutdown
-	s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	if shutdown {
-		return nil, ErrAlreadyShutdown
+	if s.suiteCtx != nil {
+		return s.suiteCtx
 	}
 
-	tracer := s.tracer
-	if tracer == nil {
-		tracer = otel.Tracer("spectra")
+	//nolint:spancheck // Ended by EndSuite.
+	ctx, span := tracer.Start(parent, s.spanName(name), trace.WithSpanKind(trace.SpanKindInternal))
+
+	s.suiteCtx = ctx
+	s.suiteSpan = span
+
+	return ctx
+}
+
+// EndSuite ends the suite span started by BeginSuite. Tests created
+// afterwards are no longer parented under it. It does nothing if no suite is
+// active.
+func (s *Spectra) EndSuite() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.suiteSpan == nil {
+		return
 	}
 
-	ctx, span := tracer.Start(
-		context.Background(),
-		tb.Name(),
+	s.suiteSpan.End()
+
+	s.suiteCtx = nil
+	s.suiteSpan = nil
+}
+
+// NewWithContext is like New but starts the test span from ctx, so the test
+// becomes a child of a span in ctx and inherits its baggage, e.g. a shared
+// suite context or an external trace.
+func (s *Spectra) NewWithContext(ctx context.Context, tb testing.TB) (*T, error) {
+	tb.Helper()
+
+	startTime := time.Now()
+	startAlloc := s.totalAlloc()
+	startGoroutines := runtime.NumGoroutine()
+
+	tracer, err := s.testTracer()
+	if err != nil {
+		return nil, err
+	}
+
+	spanParent := trace.SpanContextFromContext(ctx)
+	spanOptions := []trace.SpanStartOption{
+		trace.WithTimestamp(startTime),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(s.defaultAttributes()...),
 		trace.WithAttributes(
 			attribute.String(attrTestName, tb.Name()),
+			attribute.Int(attrHostNumCPU, runtime.NumCPU()),
+			attribute.Int(attrGoMaxProcs, runtime.GOMAXPROCS(0)),
 		),
-	)
+		trace.WithAttributes(s.envAttributes...),
+		trace.WithAttributes(s.codeLocation()...),
+		trace.WithAttributes(s.contextAttributes(ctx)...),
+	}
+
+	//nolint:spancheck // Ended by the cleanup below through t.span, which ForceSample may replace.
+	ctx, span := tracer.Start(ctx, s.testSpanName(tb.Name()), spanOptions...)
+
+	ctx, cancel := context.WithCancel(ctx)
 
 	t := &T{
-		tb:        tb,
-		ctx:       ctx,
-		span:      span,
-		tracer:    tracer,
-		spectra:   s,
-		startTime: time.Now(),
+		TB:          tb,
+		ctx:         ctx,
+		cancel:      cancel,
+		span:        span,
+		spanParent:  spanParent,
+		spanOptions: spanOptions,
+		tracer:      tracer,
+		spectra:     s,
+		startTime:   startTime,
 	}
 
+	recordTestActive(ctx, s, 1)
+
 	tb.Cleanup(func() {
 		duration := time.Since(t.startTime)
 
+		if s.config.GoroutineLeakCheck {
+			t.checkGoroutineLeak(startGoroutines)
+		}
+
+		span := t.Span()
+
 		code, message, status := t.determineStatus()
 		span.SetStatus(code, message)
+		span.SetAttributes(
+			attribute.String(attrTestStatus, status),
+			attribute.Float64(attrTestDuration, millis(duration)),
+		)
+
+		if s.config.MemoryProfiling {
+			//nolint:gosec // The bytes allocated during one test fit in an int64.
+			span.SetAttributes(attribute.Int64(attrTestAllocBytes, int64(s.totalAlloc()-startAlloc)))
+		}
 
 		span.End()
 
-		recordTestMetrics(ctx, tb.Name(), duration, status)
+		recordTestActive(ctx, s, -1)
+		recordTestMetrics(ctx, s, tb.Name(), duration, status)
+
+		s.reportTest(t.result(status, duration))
+
+		cancel()
 	})
 
+	if s.config.StdLogCapture {
+		s.logCapture.attach(t)
+		tb.Cleanup(func() { s.logCapture.detach(t) })
+	}
+
 	return t, nil
 }
 
+// parentContext returns the default parent for test spans: the suite span
+// while one is active, else the CI trace context when WithCIContextFromEnv is
+// set, otherwise context.Background().
+func (s *Spectra) parentContext() context.Context {
+	if s == nil {
+		return context.Background()
+	}
+
+	s.mu.RLock()
+	suiteCtx := s.suiteCtx
+	s.mu.RUnlock()
+
+	if suiteCtx != nil {
+		return suiteCtx
+	}
+
+	if s.parentCtx == nil {
+		return context.Background()
+	}
+
+	return s.parentCtx
+}
+
+// defaultAttributes returns the attributes set on every span s starts for a
+// test, subtest, setup or teardown.
+func (s *Spectra) defaultAttributes() []attribute.KeyValue {
+	if s == nil {
+		return nil
+	}
+
+	return s.config.DefaultAttributes
+}
+
+// contextAttributes returns the attributes ContextAttributeExtractor
+// extracts from ctx, if one is configured.
+func (s *Spectra) contextAttributes(ctx context.Context) []attribute.KeyValue {
+	if s == nil || s.config.ContextAttributeExtractor == nil {
+		return nil
+	}
+
+	return s.config.ContextAttributeExtractor(ctx)
+}
+
+// codeLocation returns the code attributes for the first caller outside
+// spectra, if CodeLocation is enabled.
+func (s *Spectra) codeLocation() []attribute.KeyValue {
+	if s == nil || !s.config.CodeLocation {
+		return nil
+	}
+
+	pkg := reflect.TypeFor[Spectra]().PkgPath() + "."
+
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, pkg) {
+			return []attribute.KeyValue{
+				semconv.CodeFilepath(frame.File),
+				semconv.CodeLineNumber(frame.Line),
+			}
+		}
+
+		if !more {
+			return nil
+		}
+	}
+}
+
+// totalAlloc returns the bytes the process has allocated so far, or 0 if
+// memory profiling is disabled. It stops the world while reading.
+func (s *Spectra) totalAlloc() uint64 {
+	if s == nil || !s.config.MemoryProfiling {
+		return 0
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	return stats.TotalAlloc
+}
+
+// reportTest passes result to the JUnit report and, for top-level tests, to
+// the test end hook.
+func (s *Spectra) reportTest(result TestResult) {
+	if s == nil {
+		return
+	}
+
+	if s.junit != nil {
+		s.junit.record(result)
+	}
+
+	if s.summary != nil {
+		s.summary.record(result)
+	}
+
+	if result.Parent == "" && s.config.TestEndHook != nil {
+		s.config.TestEndHook(result)
+	}
+}
+
+// spanName returns the span name for a test named name, prefixed with the
+// configured span name prefix.
+func (s *Spectra) spanName(name string) string {
+	if s == nil || s.config.SpanNamePrefix == "" {
+		return name
+	}
+
+	return s.config.SpanNamePrefix + "/" + name
+}
+
+// testSpanName returns the span name for a test or subtest named name,
+// computed with the configured span namer.
+func (s *Spectra) testSpanName(name string) string {
+	if s != nil && s.config.SpanNamer != nil {
+		name = s.config.SpanNamer(name)
+	}
+
+	return s.spanName(name)
+}
+
+// testTracer returns the tracer for test spans, or an error if s is not
+// initialized or already shut down.
+func (s *Spectra) testTracer() (trace.Tracer, error) {
+	if s == nil || !s.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	s.mu.RLock()
+	shutdown := s.shutdown
+	s.mu.RUnlock()
+
+	if shutdown {
+		return nil, ErrAlreadyShutdown
+	}
+
+	if s.tracer != nil {
+		return s.tracer, nil
+	}
+
+	if s.config.DisableGlobalProviders {
+		return noop.NewTracerProvider().Tracer("spectra"), nil
+	}
+
+	return otel.Tracer("spectra"), nil
+}
+
 // Name returns the name of the test.
 func (t *T) Name() string {
-	return t.tb.Name()
+	return t.TB.Name()
 }
 
 // Helper marks the calling function as a test helper function.
 func (t *T) Helper() {
-	t.tb.Helper()
+	t.TB.Helper()
 }
 
 // Cleanup registers a function to be called when the test completes.
+// With WithCleanupTracing, a cleanup.registered event is added to the span
+// now and a cleanup.run event when f runs, both carrying the registration
+// order in cleanup.index.
 func (t *T) Cleanup(f func()) {
-	t.tb.Cleanup(f)
+	if t.spectra == nil || !t.spectra.config.CleanupTracing {
+		t.TB.Cleanup(f)
+
+		return
+	}
+
+	t.mu.Lock()
+	t.cleanups++
+	index := attribute.Int(attrCleanupIndex, t.cleanups)
+	t.mu.Unlock()
+
+	t.Span().AddEvent(cleanupRegisteredEventName, trace.WithAttributes(index))
+
+	t.TB.Cleanup(func() {
+		t.Span().AddEvent(cleanupRunEventName, trace.WithAttributes(index))
+
+		f()
+	})
+}
+
+// Deadline reports the time at which the test binary will have exceeded the
+// timeout specified by the -timeout flag, and records it on the span.
+// The ok result is false if the underlying TB is not a *testing.T or there is
+// no timeout.
+func (t *T) Deadline() (time.Time, bool) {
+	tt, ok := t.TB.(*testing.T)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	deadline, ok := tt.Deadline()
+	if ok {
+		t.Span().SetAttributes(attribute.String(attrTestDeadline, deadline.Format(time.RFC3339Nano)))
+	}
+
+	return deadline, ok
+}
+
+// TempDir returns a temporary directory for the test to use and records its
+// path on the span.
+func (t *T) TempDir() string {
+	t.Helper()
+
+	dir := t.TB.TempDir()
+	if dir != "" {
+		t.Span().SetAttributes(attribute.String(attrTestTempDir, dir))
+	}
+
+	return dir
+}
+
+// Setenv sets an environment variable for the duration of the test and
+// records a "setenv" span event. Only the key is recorded, never the value.
+func (t *T) Setenv(key, value string) {
+	t.Helper()
+	t.TB.Setenv(key, value)
+
+	t.Span().AddEvent(setenvEventName, trace.WithAttributes(attribute.String(attrEnvKey, key)))
 }
 
-// Context returns the context associated with this test's span.
+// Context returns the context associated with this test's span. It is
+// cancelled when the test completes, or as soon as Fatal, Fatalf or FailNow
+// is called, so goroutines started from it stop with the test.
 func (t *T) Context() context.Context {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	return t.ctx
 }
 
-// Span returns the span associated with this test.
+// Span returns the span associated with this test. It is safe to call from
+// other goroutines, e.g. timers, while Parallel or ForceSample replace the
+// span.
 func (t *T) Span() trace.Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	return t.span
 }
 
+// TraceID returns the hex trace id of the test span, e.g. for correlating
+// logs. It returns an empty string if the span context is invalid, such as
+// when traces are disabled.
+func (t *T) TraceID() string {
+	spanContext := t.Span().SpanContext()
+	if !spanContext.IsValid() {
+		return ""
+	}
+
+	return spanContext.TraceID().String()
+}
+
+// SpanID returns the hex span id of the test span, or an empty string if the
+// span context is invalid.
+func (t *T) SpanID() string {
+	spanContext := t.Span().SpanContext()
+	if !spanContext.IsValid() {
+		return ""
+	}
+
+	return spanContext.SpanID().String()
+}
+
 // SetAttributes adds attributes to the test span.
 func (t *T) SetAttributes(attrs ...attribute.KeyValue) {
-	t.span.SetAttributes(attrs...)
+	t.Span().SetAttributes(attrs...)
 }
 
 // AddEvent adds an event to the test span.
 func (t *T) AddEvent(name string, attrs ...attribute.KeyValue) {
-	t.span.AddEvent(name, trace.WithAttributes(attrs...))
+	t.Span().AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// Eventf adds an event named by the formatted message to the test span, as a
+// human-readable timeline marker such as "started processing batch 3".
+func (t *T) Eventf(format string, args ...any) {
+	t.Span().AddEvent(formatf(format, args...))
+}
+
+// AddLink links the test span to the span identified by sc, e.g. a captured
+// production trace the test replays. Invalid span contexts are ignored.
+func (t *T) AddLink(sc trace.SpanContext, attrs ...attribute.KeyValue) {
+	if !sc.IsValid() {
+		return
+	}
+
+	t.Span().AddLink(trace.Link{SpanContext: sc, Attributes: attrs})
 }
 
 // Log logs a message and records it as a span event.
 func (t *T) Log(args ...any) {
 	t.Helper()
-	t.tb.Log(args...)
+	t.TB.Log(args...)
 
 	t.recordLog(formatArgs(args...), levelInfo)
 }
@@ -209,55 +777,135 @@ func (t *T) Log(args ...any) {
 // Logf logs a formatted message and records it as a span event.
 func (t *T) Logf(format string, args ...any) {
 	t.Helper()
-	t.tb.Logf(format, args...)
+	t.TB.Logf(format, args...)
 
 	t.recordLog(formatf(format, args...), levelInfo)
 }
 
+// Debugf logs a formatted message and, only if WithDebugLogs was set, records
+// it as a debug-level span event. Otherwise it is a cheap no-op beyond the
+// test log, keeping verbose traces out of regular runs.
+func (t *T) Debugf(format string, args ...any) {
+	t.Helper()
+	t.TB.Logf(format, args...)
+
+	if t.spectra == nil || !t.spectra.config.DebugLogs {
+		return
+	}
+
+	t.recordLog(formatf(format, args...), levelDebug)
+}
+
+// Warn logs a non-fatal warning and records it as a warn-level span event.
+// The test is not marked failed.
+func (t *T) Warn(args ...any) {
+	t.Helper()
+	t.TB.Log(args...)
+
+	t.recordLog(formatArgs(args...), levelWarn)
+}
+
+// Warnf logs a formatted non-fatal warning and records it as a warn-level
+// span event. The test is not marked failed.
+func (t *T) Warnf(format string, args ...any) {
+	t.Helper()
+	t.TB.Logf(format, args...)
+
+	t.recordLog(formatf(format, args...), levelWarn)
+}
+
+// LogKV logs message with structured key/values and records it as a span
+// event. Unlike Log, the key/values are attached to the event as attributes
+// rather than flattened into the message, so they stay queryable.
+//
+// Example:
+//
+//	st.LogKV("order created", attribute.String("order.id", id), attribute.Int("items", n))
+func (t *T) LogKV(message string, kv ...attribute.KeyValue) {
+	t.Helper()
+	t.TB.Log(formatKV(message, kv...))
+
+	t.recordLog(message, levelInfo, kv...)
+}
+
 // Error logs an error and records it as a span event.
+// The first error argument, or the message itself, is also recorded as an
+// exception on the span.
 func (t *T) Error(args ...any) {
 	t.Helper()
 
 	t.setFailed()
 
-	t.tb.Error(args...)
+	t.TB.Error(args...)
 
-	t.recordLog(formatArgs(args...), levelError)
+	message := formatArgs(args...)
+	t.recordLog(message, levelError)
+	t.Span().RecordError(errorFromArgs(message, args...))
 }
 
 // Errorf logs a formatted error and records it as a span event.
+// The first error argument, or the formatted message, is also recorded as an
+// exception on the span.
 func (t *T) Errorf(format string, args ...any) {
 	t.Helper()
 
 	t.setFailed()
 
-	t.tb.Errorf(format, args...)
+	t.TB.Errorf(format, args...)
+
+	message := formatf(format, args...)
+	t.recordLog(message, levelError)
+	t.Span().RecordError(errorFromArgs(message, args...))
+}
+
+// RecordError reports err as a test error and records it as an exception
+// with a stack trace on the span, carrying attrs. The span is marked as
+// failed and err is logged with %+v, so errors that format their own stack
+// trace include it in the test output. A nil err is ignored.
+func (t *T) RecordError(err error, attrs ...attribute.KeyValue) {
+	t.Helper()
+
+	if err == nil {
+		return
+	}
+
+	t.setFailed()
+
+	t.TB.Errorf("%+v", err)
 
-	t.recordLog(formatf(format, args...), levelError)
+	t.recordLog(err.Error(), levelError)
+	t.Span().RecordError(err, trace.WithStackTrace(true), trace.WithAttributes(attrs...))
+	t.Span().SetStatus(codes.Error, "test error")
 }
 
-// Fatal logs a fatal error and records it as a span event.
+// Fatal logs a fatal error and records it as a span event and exception.
 func (t *T) Fatal(args ...any) {
 	t.Helper()
 
 	t.setFailed()
 
-	t.recordLog(formatArgs(args...), levelFatal)
+	message := formatArgs(args...)
+	t.recordLog(message, levelFatal)
+	t.Span().RecordError(errorFromArgs(message, args...))
 
-	t.span.SetStatus(codes.Error, "test fatal")
-	t.tb.Fatal(args...)
+	t.Span().SetStatus(codes.Error, "test fatal")
+	t.cancel()
+	t.TB.Fatal(args...)
 }
 
-// Fatalf logs a formatted fatal error and records it as a span event.
+// Fatalf logs a formatted fatal error and records it as a span event and exception.
 func (t *T) Fatalf(format string, args ...any) {
 	t.Helper()
 
 	t.setFailed()
 
-	t.recordLog(formatf(format, args...), levelFatal)
+	message := formatf(format, args...)
+	t.recordLog(message, levelFatal)
+	t.Span().RecordError(errorFromArgs(message, args...))
 
-	t.span.SetStatus(codes.Error, "test fatal")
-	t.tb.Fatalf(format, args...)
+	t.Span().SetStatus(codes.Error, "test fatal")
+	t.cancel()
+	t.TB.Fatalf(format, args...)
 }
 
 // Skip logs a skip message and records it as a span event.
@@ -266,8 +914,8 @@ func (t *T) Skip(args ...any) {
 
 	t.recordLog(formatArgs(args...), levelSkip)
 
-	t.span.SetStatus(codes.Ok, "test skipped")
-	t.tb.Skip(args...)
+	t.Span().SetStatus(codes.Ok, "test skipped")
+	t.TB.Skip(args...)
 }
 
 // Skipf logs a formatted skip message and records it as a span event.
@@ -276,8 +924,8 @@ func (t *T) Skipf(format string, args ...any) {
 
 	t.recordLog(formatf(format, args...), levelSkip)
 
-	t.span.SetStatus(codes.Ok, "test skipped")
-	t.tb.Skipf(format, args...)
+	t.Span().SetStatus(codes.Ok, "test skipped")
+	t.TB.Skipf(format, args...)
 }
 
 // FailNow marks the test as failed and stops its execution.
@@ -288,8 +936,9 @@ func (t *T) FailNow() {
 
 	t.recordLog("test failed", levelFatal)
 
-	t.span.SetStatus(codes.Error, "test failed")
-	t.tb.FailNow()
+	t.Span().SetStatus(codes.Error, "test failed")
+	t.cancel()
+	t.TB.FailNow()
 }
 
 // SkipNow marks the test as skipped and stops its execution.
@@ -298,8 +947,52 @@ func (t *T) SkipNow() {
 
 	t.recordLog("test skipped", levelSkip)
 
-	t.span.SetStatus(codes.Ok, "test skipped")
-	t.tb.SkipNow()
+	t.Span().SetStatus(codes.Ok, "test skipped")
+	t.TB.SkipNow()
+}
+
+// RecoverPanic records a panic in the test as an exception with a stack trace
+// and marks the span as failed, then re-panics so the test runner still
+// reports it. It must be deferred directly, before the code that may panic.
+// Subtests started with Run are covered automatically.
+//
+// Example:
+//
+//	func TestFeature(t *testing.T) {
+//	    st, err := sp.New(t)
+//	    if err != nil {
+//	        t.Fatalf("spectra: %v", err)
+//	    }
+//	    defer st.RecoverPanic()
+//	}
+func (t *T) RecoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	t.setFailed()
+
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("%w: %v", errPanic, r)
+	}
+
+	t.Span().RecordError(err, trace.WithStackTrace(true))
+	t.Span().SetStatus(codes.Error, "test panicked")
+
+	panic(r)
+}
+
+// Failed reports whether the test has failed, either through T or directly
+// through the underlying TB.
+func (t *T) Failed() bool {
+	return t.hasFailed() || t.TB.Failed()
+}
+
+// Skipped reports whether the test was skipped.
+func (t *T) Skipped() bool {
+	return t.TB.Skipped()
 }
 
 func (t *T) setFailed() {
@@ -316,22 +1009,64 @@ func (t *T) hasFailed() bool {
 	return t.failed
 }
 
-func (t *T) recordLog(message, level string) {
+// recordLog records message as a log event on the span. Error and fatal
+// messages are also kept for reports such as JUnit, even when log events are
+// disabled.
+func (t *T) recordLog(message, level string, attrs ...attribute.KeyValue) {
+	if t.spectra != nil && t.spectra.config.LogScrubber != nil {
+		message = t.spectra.config.LogScrubber(message)
+	}
+
+	if level == levelError || level == levelFatal {
+		t.mu.Lock()
+		t.errors = append(t.errors, message)
+		t.mu.Unlock()
+	}
+
 	if t.spectra != nil && t.spectra.config.DisableLogs {
 		return
 	}
 
-	t.span.AddEvent(logEventName, trace.WithAttributes(
-		attribute.String(attrMessage, message),
-		attribute.String(attrLevel, level),
-	))
+	t.Span().AddEvent(logEventName,
+		trace.WithAttributes(
+			attribute.String(attrMessage, message),
+			attribute.String(attrLevel, level),
+			attribute.Int(attrSeverityNumber, int(logSeverity(level))),
+		),
+		trace.WithAttributes(attrs...),
+	)
+
+	t.emitLog(message, level, attrs...)
+}
+
+// result describes t once it has completed with status after duration.
+func (t *T) result(status string, duration time.Duration) TestResult {
+	traceID := t.TraceID()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := TestResult{
+		Name:     t.Name(),
+		Status:   status,
+		Duration: duration,
+		TraceID:  traceID,
+		Failed:   status == statusFail,
+		Errors:   slices.Clone(t.errors),
+	}
+
+	if t.parent != nil {
+		result.Parent = t.parent.Name()
+	}
+
+	return result
 }
 
 func (t *T) determineStatus() (codes.Code, string, string) {
 	switch {
-	case t.hasFailed() || t.tb.Failed():
+	case t.Failed():
 		return codes.Error, "test failed", statusFail
-	case t.tb.Skipped():
+	case t.Skipped():
 		return codes.Ok, "test skipped", statusSkip
 	default:
 		return codes.Ok, "test passed", statusPass
@@ -2,19 +2,58 @@ package spectra_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"encoding/xml"
 	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	stdlog "log"
+	"maps"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/monkescience/spectra"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
 )
 
-func setupTestTracer(t *testing.T) (*tracetest.InMemoryExporter, *spectra.Spectra) {
-	t.Helper()
+func setupTestTracer(tb testing.TB) (*tracetest.InMemoryExporter, *spectra.Spectra) {
+	tb.Helper()
 
 	exporter := tracetest.NewInMemoryExporter()
 	tp := sdktrace.NewTracerProvider(
@@ -29,12 +68,16 @@ func setupTestTracer(t *testing.T) (*tracetest.InMemoryExporter, *spectra.Spectr
 		spectra.WithoutMetrics(),
 	)
 	if err != nil {
-		t.Fatalf("failed to init spectra: %v", err)
+		tb.Fatalf("failed to init spectra: %v", err)
 	}
 
-	t.Cleanup(func() {
+	tb.Cleanup(func() {
 		_ = tp.Shutdown(context.Background())
-		sp.Shutdown()
+
+		err := sp.Shutdown()
+		if err != nil {
+			tb.Errorf("unexpected shutdown error: %v", err)
+		}
 	})
 
 	return exporter, sp
@@ -47,6 +90,8 @@ type mockTB struct {
 	cleanups []func()
 	failed   bool
 	skipped  bool
+	tempDir  string
+	env      map[string]string
 }
 
 func newMockTB(name string) *mockTB {
@@ -66,184 +111,369 @@ func (m *mockTB) Skipf(_ string, _ ...any)  { m.skipped = true }
 func (m *mockTB) Failed() bool              { return m.failed }
 func (m *mockTB) Skipped() bool             { return m.skipped }
 func (m *mockTB) Cleanup(f func())          { m.cleanups = append(m.cleanups, f) }
-func (m *mockTB) TempDir() string           { return "" }
-func (m *mockTB) Setenv(_ string, _ string) {}
+func (m *mockTB) TempDir() string           { return m.tempDir }
 func (m *mockTB) FailNow()                  { m.failed = true }
 func (m *mockTB) Fail()                     { m.failed = true }
 func (m *mockTB) SkipNow()                  { m.skipped = true }
 
+func (m *mockTB) Setenv(key, value string) {
+	if m.env == nil {
+		m.env = make(map[string]string)
+	}
+
+	m.env[key] = value
+}
+
 func (m *mockTB) runCleanups() {
 	for i := len(m.cleanups) - 1; i >= 0; i-- {
 		m.cleanups[i]()
 	}
 }
 
-func TestNew(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+// memoryLogExporter is an sdklog.Exporter that keeps exported records in memory.
+type memoryLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
 
-	// given
-	exporter, sp := setupTestTracer(t)
+func (e *memoryLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
-	// when - run in subtest so span completes.
-	t.Run("creates_span", func(innerT *testing.T) {
-		st, err := sp.New(innerT)
-		if err != nil {
-			innerT.Fatalf("failed to create test: %v", err)
-		}
+	for _, record := range records {
+		e.records = append(e.records, record.Clone())
+	}
 
-		st.Log("test message")
-	})
+	return nil
+}
 
-	// then - check spans after subtest completes.
-	spans := exporter.GetSpans()
-	if len(spans) == 0 {
-		t.Fatal("expected at least one span")
-	}
+func (e *memoryLogExporter) Shutdown(context.Context) error   { return nil }
+func (e *memoryLogExporter) ForceFlush(context.Context) error { return nil }
 
-	found := false
+func (e *memoryLogExporter) Records() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
-	for _, span := range spans {
-		if span.Name == "TestNew/creates_span" {
-			found = true
+	return slices.Clone(e.records)
+}
 
-			break
+// countingProcessor is an sdktrace.SpanProcessor that counts span starts and
+// records the order in which processors see each span end.
+type countingProcessor struct {
+	name    string
+	started int
+	order   *[]string
+}
+
+func (p *countingProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) { p.started++ }
+func (p *countingProcessor) OnEnd(sdktrace.ReadOnlySpan)                     { *p.order = append(*p.order, p.name) }
+func (p *countingProcessor) Shutdown(context.Context) error                  { return nil }
+func (p *countingProcessor) ForceFlush(context.Context) error                { return nil }
+
+// traceCollector is an OTLP trace service that records exported span names.
+type traceCollector struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	mu    sync.Mutex
+	names []string
+}
+
+func (c *traceCollector) Export(
+	_ context.Context,
+	req *collectortrace.ExportTraceServiceRequest,
+) (*collectortrace.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				c.names = append(c.names, span.GetName())
+			}
 		}
 	}
 
-	if !found {
-		t.Error("expected span with test name not found")
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+func (c *traceCollector) Names() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.Clone(c.names)
+}
+
+// metricCollector is an OTLP metrics service that accepts every export.
+type metricCollector struct {
+	collectormetrics.UnimplementedMetricsServiceServer
+}
+
+func (metricCollector) Export(
+	context.Context,
+	*collectormetrics.ExportMetricsServiceRequest,
+) (*collectormetrics.ExportMetricsServiceResponse, error) {
+	return &collectormetrics.ExportMetricsServiceResponse{}, nil
+}
+
+// startUnixCollector serves a traceCollector on a unix socket until t completes
+// and returns the socket path.
+func startUnixCollector(t *testing.T) (string, *traceCollector) {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "otel.sock")
+
+	listener, err := (&net.ListenConfig{}).Listen(context.Background(), "unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen on unix socket: %v", err)
 	}
+
+	return socket, serveCollector(t, listener)
 }
 
-func TestT_Log(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+// startGRPCCollector serves a traceCollector on a local TCP port until t
+// completes and returns its grpc:// endpoint.
+func startGRPCCollector(t *testing.T) (string, *traceCollector) {
+	t.Helper()
 
-	// given
-	exporter, sp := setupTestTracer(t)
+	listener, err := (&net.ListenConfig{}).Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen on tcp: %v", err)
+	}
 
-	// when
-	t.Run("logs_message", func(innerT *testing.T) {
-		st, err := sp.New(innerT)
-		if err != nil {
-			innerT.Fatalf("failed to create test: %v", err)
-		}
+	return "grpc://" + listener.Addr().String(), serveCollector(t, listener)
+}
 
-		st.Log("hello", "world")
-		st.Logf("formatted %s", "message")
-	})
+// serveCollector serves OTLP traces and metrics over gRPC on listener until t
+// completes.
+func serveCollector(t *testing.T, listener net.Listener) *traceCollector {
+	t.Helper()
 
-	// then
-	spans := exporter.GetSpans()
-	if len(spans) == 0 {
-		t.Fatal("expected at least one span")
+	collector := &traceCollector{}
+	server := grpc.NewServer()
+	collectortrace.RegisterTraceServiceServer(server, collector)
+	collectormetrics.RegisterMetricsServiceServer(server, metricCollector{})
+
+	go func() { _ = server.Serve(listener) }()
+
+	t.Cleanup(server.Stop)
+
+	return collector
+}
+
+// startHTTPCollector serves OTLP over HTTP until t completes, accepting every
+// export.
+func startHTTPCollector(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(acceptExport))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func acceptExport(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeTestCertificate writes a self-signed certificate and its key as PEM files.
+func writeTestCertificate(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
 	}
 
-	var targetSpan tracetest.SpanStub
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "spectra-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
 
-	for _, s := range spans {
-		if s.Name == "TestT_Log/logs_message" {
-			targetSpan = s
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
 
-			break
-		}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
 	}
 
-	events := targetSpan.Events
-	if len(events) < 2 {
-		t.Fatalf("expected at least 2 events, got %d", len(events))
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
 	}
 
-	if events[0].Name != "log" {
-		t.Errorf("expected event name 'log', got %q", events[0].Name)
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write key: %v", err)
 	}
+
+	return certFile, keyFile
 }
 
-func TestT_SetAttributes(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+// durationHistogram returns the test.duration data point recorded for testName.
+func durationHistogram(
+	t *testing.T,
+	reader *sdkmetric.ManualReader,
+	testName string,
+) metricdata.HistogramDataPoint[float64] {
+	t.Helper()
 
-	// given
-	exporter, sp := setupTestTracer(t)
+	var rm metricdata.ResourceMetrics
 
-	// when
-	t.Run("sets_attributes", func(innerT *testing.T) {
-		st, err := sp.New(innerT)
-		if err != nil {
-			innerT.Fatalf("failed to create test: %v", err)
+	err := reader.Collect(context.Background(), &rm)
+	if err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "test.duration" {
+				continue
+			}
+
+			hist, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok {
+				t.Fatalf("expected test.duration to be Histogram[float64], got %T", m.Data)
+			}
+
+			for _, dp := range hist.DataPoints {
+				if name, _ := dp.Attributes.Value("test.name"); name.AsString() == testName {
+					return dp
+				}
+			}
 		}
+	}
 
-		st.SetAttributes(
-			attribute.String("custom.key", "custom.value"),
-			attribute.Int("custom.number", 42),
-		)
-	})
+	t.Fatalf("no test.duration data point for %q", testName)
 
-	// then
-	spans := exporter.GetSpans()
-	if len(spans) == 0 {
-		t.Fatal("expected at least one span")
+	return metricdata.HistogramDataPoint[float64]{}
+}
+
+// activeTests returns the current value of the test.active counter.
+func activeTests(tb testing.TB, reader *sdkmetric.ManualReader) int64 {
+	tb.Helper()
+
+	var rm metricdata.ResourceMetrics
+
+	err := reader.Collect(context.Background(), &rm)
+	if err != nil {
+		tb.Fatalf("failed to collect metrics: %v", err)
 	}
 
-	var targetSpan tracetest.SpanStub
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "test.active" {
+				continue
+			}
 
-	for _, s := range spans {
-		if s.Name == "TestT_SetAttributes/sets_attributes" {
-			targetSpan = s
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				tb.Fatalf("expected test.active to be Sum[int64], got %T", m.Data)
+			}
 
-			break
+			var total int64
+			for _, dp := range sum.DataPoints {
+				total += dp.Value
+			}
+
+			return total
 		}
 	}
 
-	found := false
+	return 0
+}
 
-	for _, attr := range targetSpan.Attributes {
-		if attr.Key == "custom.key" && attr.Value.AsString() == "custom.value" {
-			found = true
+// countedTestNames returns the test.name attribute of every data point of the
+// counter named metricName.
+func countedTestNames(t *testing.T, reader *sdkmetric.ManualReader, metricName string) []string {
+	t.Helper()
 
-			break
+	var rm metricdata.ResourceMetrics
+
+	err := reader.Collect(context.Background(), &rm)
+	if err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	var names []string
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != metricName {
+				continue
+			}
+
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				t.Fatalf("expected %s to be Sum[int64], got %T", metricName, m.Data)
+			}
+
+			for _, dp := range sum.DataPoints {
+				name, _ := dp.Attributes.Value("test.name")
+				names = append(names, name.AsString())
+			}
 		}
 	}
 
-	if !found {
-		t.Error("expected custom attribute not found")
+	return names
+}
+
+// eventAttributes returns the attributes of the first event named name.
+func eventAttributes(span tracetest.SpanStub, name string) map[attribute.Key]attribute.Value {
+	for _, event := range span.Events {
+		if event.Name != name {
+			continue
+		}
+
+		attrs := make(map[attribute.Key]attribute.Value, len(event.Attributes))
+		for _, attr := range event.Attributes {
+			attrs[attr.Key] = attr.Value
+		}
+
+		return attrs
 	}
+
+	return nil
 }
 
-func TestT_AddEvent(t *testing.T) {
+func TestNew(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
 	exporter, sp := setupTestTracer(t)
 
-	// when
-	t.Run("adds_event", func(innerT *testing.T) {
+	// when - run in subtest so span completes.
+	t.Run("creates_span", func(innerT *testing.T) {
 		st, err := sp.New(innerT)
 		if err != nil {
 			innerT.Fatalf("failed to create test: %v", err)
 		}
 
-		st.AddEvent("custom.event", attribute.String("key", "value"))
+		st.Log("test message")
 	})
 
-	// then
+	// then - check spans after subtest completes.
 	spans := exporter.GetSpans()
 	if len(spans) == 0 {
 		t.Fatal("expected at least one span")
 	}
 
-	var targetSpan tracetest.SpanStub
-
-	for _, s := range spans {
-		if s.Name == "TestT_AddEvent/adds_event" {
-			targetSpan = s
-
-			break
-		}
-	}
-
 	found := false
 
-	for _, event := range targetSpan.Events {
-		if event.Name == "custom.event" {
+	for _, span := range spans {
+		if span.Name == "TestNew/creates_span" {
 			found = true
 
 			break
@@ -251,798 +481,7485 @@ func TestT_AddEvent(t *testing.T) {
 	}
 
 	if !found {
-		t.Error("expected custom event not found")
+		t.Error("expected span with test name not found")
 	}
 }
 
-func TestT_Context(t *testing.T) {
+func TestNewWithContext(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
-	_, sp := setupTestTracer(t)
+	exporter, sp := setupTestTracer(t)
 
-	st, err := sp.New(t)
-	if err != nil {
-		t.Fatalf("failed to create test: %v", err)
-	}
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), parent)
 
 	// when
-	ctx := st.Context()
+	t.Run("child", func(innerT *testing.T) {
+		_, err := sp.NewWithContext(ctx, innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+	})
 
 	// then
-	if ctx == nil {
-		t.Error("expected non-nil context")
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].SpanContext.TraceID() != parent.TraceID() {
+		t.Errorf("expected trace id %s, got %s", parent.TraceID(), spans[0].SpanContext.TraceID())
+	}
+
+	if spans[0].Parent.SpanID() != parent.SpanID() {
+		t.Errorf("expected parent span id %s, got %s", parent.SpanID(), spans[0].Parent.SpanID())
 	}
 }
 
-func TestT_Span(t *testing.T) {
+func TestSpectra_BeginSuite(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
-	_, sp := setupTestTracer(t)
+	exporter, sp := setupTestTracer(t)
 
-	st, err := sp.New(t)
-	if err != nil {
-		t.Fatalf("failed to create test: %v", err)
-	}
+	suiteCtx := sp.BeginSuite("suite")
 
 	// when
-	span := st.Span()
+	t.Run("parallel", func(innerT *testing.T) {
+		for _, name := range []string{"first", "second"} {
+			innerT.Run(name, func(testT *testing.T) {
+				testT.Parallel()
+
+				_, err := sp.New(testT)
+				if err != nil {
+					testT.Errorf("failed to create test: %v", err)
+				}
+			})
+		}
+	})
+
+	sp.EndSuite()
+
+	t.Run("after_suite", func(innerT *testing.T) {
+		_, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+	})
 
 	// then
-	if span == nil {
-		t.Error("expected non-nil span")
+	suite := trace.SpanContextFromContext(suiteCtx)
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
 	}
 
-	if !span.SpanContext().IsValid() {
-		t.Error("expected valid span context")
+	if _, ok := spans["suite"]; !ok {
+		t.Fatal("expected the suite span to be ended and exported")
 	}
-}
 
-func TestT_Run(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+	for _, name := range []string{"TestSpectra_BeginSuite/parallel/first", "TestSpectra_BeginSuite/parallel/second"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Fatalf("expected span %q", name)
+		}
 
-	// given
-	exporter, sp := setupTestTracer(t)
+		if span.SpanContext.TraceID() != suite.TraceID() {
+			t.Errorf("expected %q to share the suite trace id", name)
+		}
 
-	// when - run parent and subtest.
-	t.Run("parent", func(innerT *testing.T) {
-		st, err := sp.New(innerT)
-		if err != nil {
-			innerT.Fatalf("failed to create test: %v", err)
+		if span.Parent.SpanID() != suite.SpanID() {
+			t.Errorf("expected %q to be a child of the suite span", name)
 		}
+	}
 
-		st.Run("subtest", func(subST *spectra.T) {
-			subST.Log("subtest message")
-		})
-	})
+	if spans["TestSpectra_BeginSuite/after_suite"].SpanContext.TraceID() == suite.TraceID() {
+		t.Error("expected tests after EndSuite not to join the suite trace")
+	}
+}
+
+// blockingExporter is a span exporter whose Shutdown blocks until its context
+// is done.
+type blockingExporter struct {
+	tracetest.InMemoryExporter
+}
+
+func (e *blockingExporter) Shutdown(ctx context.Context) error {
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func TestSpectra_ShutdownContext(t *testing.T) {
+	t.Parallel()
+
+	// given
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(&blockingExporter{}),
+		spectra.WithShutdownTimeout(time.Minute),
+		spectra.WithGlobalProviders(false),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// when
+	start := time.Now()
+	err = sp.ShutdownContext(ctx)
 
 	// then
-	spans := exporter.GetSpans()
-	if len(spans) < 2 {
-		t.Fatalf("expected at least 2 spans (parent + subtest), got %d", len(spans))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
 	}
 
-	// Verify both parent and child spans exist.
-	parentFound := false
-	childFound := false
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected shutdown to return promptly, took %v", elapsed)
+	}
+}
 
-	for _, s := range spans {
-		if s.Name == "TestT_Run/parent" {
-			parentFound = true
-		}
+func TestSpectra_ShutdownEndsSuite(t *testing.T) {
+	t.Parallel()
 
-		if s.Name == "TestT_Run/parent/subtest" {
-			childFound = true
-		}
+	// given
+	recorder := tracetest.NewSpanRecorder()
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(tracetest.NewInMemoryExporter()),
+		spectra.WithSpanProcessor(recorder),
+		spectra.WithGlobalProviders(false),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if !parentFound {
-		t.Error("expected parent span not found")
+	sp.BeginSuite("suite")
+
+	// when
+	err = sp.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
 	}
 
-	if !childFound {
-		t.Error("expected child span not found")
+	st, err := sp.New(newMockTB("after_shutdown"))
+
+	// then
+	if !errors.Is(err, spectra.ErrAlreadyShutdown) {
+		t.Errorf("expected ErrAlreadyShutdown, got %v", err)
+	}
+
+	if st != nil {
+		t.Error("expected no test to be returned after shutdown")
+	}
+
+	ended := recorder.Ended()
+	if len(ended) != 1 || ended[0].Name() != "suite" {
+		t.Errorf("expected the suite span to be ended on shutdown, got %v", ended)
 	}
 }
 
-func TestT_StartSpan(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+func TestNew_CIContextFromEnv(t *testing.T) {
+	// Tests modify environment variables and global tracer provider - cannot run in parallel.
 
 	// given
-	exporter, sp := setupTestTracer(t)
+	t.Setenv("TRACEPARENT", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	t.Setenv("TRACESTATE", "vendor=value")
 
-	// when
-	t.Run("creates_child_span", func(innerT *testing.T) {
-		st, err := sp.New(innerT)
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithoutTraces(),
+		spectra.WithoutMetrics(),
+		spectra.WithCIContextFromEnv(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
 		if err != nil {
-			innerT.Fatalf("failed to create test: %v", err)
+			t.Errorf("unexpected shutdown error: %v", err)
 		}
+	}()
 
-		ctx, span := st.StartSpan("custom-operation")
-		span.End()
-
-		if ctx == nil {
-			innerT.Error("expected non-nil context")
+	// when
+	t.Run("in_ci", func(innerT *testing.T) {
+		_, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
 		}
 	})
 
 	// then
 	spans := exporter.GetSpans()
-	found := false
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
 
-	for _, s := range spans {
-		if s.Name == "custom-operation" {
-			found = true
+	if got := spans[0].SpanContext.TraceID().String(); got != "0af7651916cd43dd8448eb211c80319c" {
+		t.Errorf("expected CI trace id, got %s", got)
+	}
 
-			break
-		}
+	if got := spans[0].Parent.SpanID().String(); got != "b7ad6b7169203331" {
+		t.Errorf("expected CI parent span id, got %s", got)
 	}
 
-	if !found {
-		t.Error("expected custom span not found")
+	if got := spans[0].SpanContext.TraceState().Get("vendor"); got != "value" {
+		t.Errorf("expected trace state to be propagated, got %q", got)
 	}
 }
 
-func TestT_Setup(t *testing.T) {
+func TestT_Log(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
 	exporter, sp := setupTestTracer(t)
 
 	// when
-	t.Run("runs_setup", func(innerT *testing.T) {
+	t.Run("logs_message", func(innerT *testing.T) {
 		st, err := sp.New(innerT)
 		if err != nil {
 			innerT.Fatalf("failed to create test: %v", err)
 		}
 
-		setupCalled := false
-
-		st.Setup(func(_ context.Context) {
-			setupCalled = true
-		})
-
-		if !setupCalled {
-			innerT.Error("expected setup function to be called")
-		}
+		st.Log("hello", "world")
+		st.Logf("formatted %s", "message")
 	})
 
 	// then
 	spans := exporter.GetSpans()
-	found := false
+	if len(spans) == 0 {
+		t.Fatal("expected at least one span")
+	}
+
+	var targetSpan tracetest.SpanStub
 
 	for _, s := range spans {
-		if s.Name == "TestT_Setup/runs_setup/setup" {
-			found = true
+		if s.Name == "TestT_Log/logs_message" {
+			targetSpan = s
 
 			break
 		}
 	}
 
-	if !found {
-		t.Error("expected setup span not found")
+	events := targetSpan.Events
+	if len(events) < 2 {
+		t.Fatalf("expected at least 2 events, got %d", len(events))
+	}
+
+	if events[0].Name != "log" {
+		t.Errorf("expected event name 'log', got %q", events[0].Name)
 	}
 }
 
-func TestT_Teardown(t *testing.T) {
+func TestT_Log_SeverityNumber(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
-	// given
-	exporter, sp := setupTestTracer(t)
-	teardownCalled := false
-
-	// when
-	t.Run("runs_teardown", func(innerT *testing.T) {
-		st, err := sp.New(innerT)
-		if err != nil {
-			innerT.Fatalf("failed to create test: %v", err)
-		}
+	tests := []struct {
+		name       string
+		log        func(st *spectra.T)
+		wantLevel  string
+		wantNumber int64
+	}{
+		{name: "info", log: func(st *spectra.T) { st.Log("hello") }, wantLevel: "info", wantNumber: 9},
+		{name: "warn", log: func(st *spectra.T) { st.Warn("retried") }, wantLevel: "warn", wantNumber: 13},
+		{name: "warnf", log: func(st *spectra.T) { st.Warnf("retried %d times", 2) }, wantLevel: "warn", wantNumber: 13},
+		{name: "error", log: func(st *spectra.T) { st.Error("boom") }, wantLevel: "error", wantNumber: 17},
+		{name: "fatal", log: func(st *spectra.T) { st.Fatal("stop") }, wantLevel: "fatal", wantNumber: 21},
+		{name: "skip", log: func(st *spectra.T) { st.Skip("later") }, wantLevel: "skip", wantNumber: 5},
+	}
 
-		st.Teardown(func(_ context.Context) {
-			teardownCalled = true
-		})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			exporter, sp := setupTestTracer(t)
+			mock := newMockTB(tt.name)
 
-		// Teardown hasn't been called yet.
-		if teardownCalled {
-			innerT.Error("teardown should not be called until cleanup")
-		}
-	})
+			st, err := sp.New(mock)
+			if err != nil {
+				t.Fatalf("failed to create test: %v", err)
+			}
 
-	// then - after subtest completes, teardown should have run.
-	if !teardownCalled {
-		t.Error("expected teardown to be called after test cleanup")
-	}
+			// when
+			tt.log(st)
+			mock.runCleanups()
 
-	spans := exporter.GetSpans()
-	found := false
+			// then
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
 
-	for _, s := range spans {
-		if s.Name == "TestT_Teardown/runs_teardown/teardown" {
-			found = true
+			attrs := eventAttributes(spans[0], "log")
+			if attrs == nil {
+				t.Fatal("expected log event")
+			}
 
-			break
-		}
-	}
+			if got := attrs["level"].AsString(); got != tt.wantLevel {
+				t.Errorf("expected level %q, got %q", tt.wantLevel, got)
+			}
 
-	if !found {
-		t.Error("expected teardown span not found")
+			if got := attrs["severity.number"].AsInt64(); got != tt.wantNumber {
+				t.Errorf("expected severity.number %d, got %d", tt.wantNumber, got)
+			}
+		})
 	}
 }
 
-func TestT_SpanStatus_Pass(t *testing.T) {
+func TestT_Log_Scrubber(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
-	exporter, sp := setupTestTracer(t)
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
 
-	// when - run a passing test.
-	t.Run("passing", func(innerT *testing.T) {
-		_, err := sp.New(innerT)
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	token := regexp.MustCompile(`Bearer \S+`)
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithoutTraces(),
+		spectra.WithoutMetrics(),
+		spectra.WithLogScrubber(func(message string) string {
+			return token.ReplaceAllString(message, "Bearer [REDACTED]")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to init spectra: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
 		if err != nil {
-			innerT.Fatalf("failed to create test: %v", err)
+			t.Errorf("unexpected shutdown error: %v", err)
 		}
-		// Test passes without any errors.
-	})
+	}()
+
+	mock := newMockTB("scrubbed")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	st.Logf("calling API with Authorization: %s", "Bearer s3cr3t-t0k3n")
+	mock.runCleanups()
 
 	// then
 	spans := exporter.GetSpans()
-	found := false
-
-	for _, s := range spans {
-		if s.Name == "TestT_SpanStatus_Pass/passing" && s.Status.Code == codes.Ok {
-			found = true
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
 
-			break
-		}
+	attrs := eventAttributes(spans[0], "log")
+	if attrs == nil {
+		t.Fatal("expected log event")
 	}
 
-	if !found {
-		t.Error("expected span with Ok status not found")
+	want := "calling API with Authorization: Bearer [REDACTED]"
+	if got := attrs["message"].AsString(); got != want {
+		t.Errorf("expected message %q, got %q", want, got)
 	}
 }
 
-func TestT_Error(t *testing.T) {
+func TestT_SetAttributes(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
 	exporter, sp := setupTestTracer(t)
-	mock := newMockTB("TestT_Error")
 
 	// when
-	st, err := sp.New(mock)
-	if err != nil {
-		t.Fatalf("failed to create test: %v", err)
-	}
+	t.Run("sets_attributes", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
 
-	st.Error("test error message")
-	st.Errorf("formatted error: %s", "details")
-	mock.runCleanups()
+		st.SetAttributes(
+			attribute.String("custom.key", "custom.value"),
+			attribute.Int("custom.number", 42),
+		)
+	})
 
 	// then
 	spans := exporter.GetSpans()
+	if len(spans) == 0 {
+		t.Fatal("expected at least one span")
+	}
 
 	var targetSpan tracetest.SpanStub
 
 	for _, s := range spans {
-		if s.Name == "TestT_Error" {
+		if s.Name == "TestT_SetAttributes/sets_attributes" {
 			targetSpan = s
 
 			break
 		}
 	}
 
-	errorEvents := 0
+	found := false
 
-	for _, event := range targetSpan.Events {
-		if event.Name == "log" {
-			for _, attr := range event.Attributes {
-				if attr.Key == "level" && attr.Value.AsString() == "error" {
-					errorEvents++
-				}
-			}
-		}
-	}
+	for _, attr := range targetSpan.Attributes {
+		if attr.Key == "custom.key" && attr.Value.AsString() == "custom.value" {
+			found = true
 
-	if errorEvents < 2 {
-		t.Errorf("expected at least 2 error events, got %d", errorEvents)
+			break
+		}
 	}
 
-	if !mock.failed {
-		t.Error("expected mock to be marked as failed")
+	if !found {
+		t.Error("expected custom attribute not found")
 	}
 }
 
-func TestT_Fatal(t *testing.T) {
+func TestT_AddEvent(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
 	exporter, sp := setupTestTracer(t)
-	mock := newMockTB("TestT_Fatal")
 
 	// when
-	st, err := sp.New(mock)
-	if err != nil {
-		t.Fatalf("failed to create test: %v", err)
-	}
+	t.Run("adds_event", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
 
-	st.Fatal("fatal error")
-	mock.runCleanups()
+		st.AddEvent("custom.event", attribute.String("key", "value"))
+	})
 
 	// then
 	spans := exporter.GetSpans()
+	if len(spans) == 0 {
+		t.Fatal("expected at least one span")
+	}
 
 	var targetSpan tracetest.SpanStub
 
 	for _, s := range spans {
-		if s.Name == "TestT_Fatal" {
+		if s.Name == "TestT_AddEvent/adds_event" {
 			targetSpan = s
 
 			break
 		}
 	}
 
-	fatalFound := false
+	found := false
 
 	for _, event := range targetSpan.Events {
-		if event.Name == "log" {
-			for _, attr := range event.Attributes {
-				if attr.Key == "level" && attr.Value.AsString() == "fatal" {
-					fatalFound = true
-				}
-			}
-		}
-	}
+		if event.Name == "custom.event" {
+			found = true
 
-	if !fatalFound {
-		t.Error("expected fatal log event not found")
+			break
+		}
 	}
 
-	if targetSpan.Status.Code != codes.Error {
-		t.Error("expected span status to be Error")
+	if !found {
+		t.Error("expected custom event not found")
 	}
 }
 
-func TestT_Fatalf(t *testing.T) {
+func TestT_Eventf(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
 	exporter, sp := setupTestTracer(t)
-	mock := newMockTB("TestT_Fatalf")
 
 	// when
-	st, err := sp.New(mock)
-	if err != nil {
-		t.Fatalf("failed to create test: %v", err)
-	}
+	t.Run("adds_formatted_event", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
 
-	st.Fatalf("fatal error: %s", "formatted")
-	mock.runCleanups()
+		st.Eventf("started processing batch %d of %s", 3, "orders")
+	})
 
 	// then
-	spans := exporter.GetSpans()
-
 	var targetSpan tracetest.SpanStub
 
-	for _, s := range spans {
-		if s.Name == "TestT_Fatalf" {
+	for _, s := range exporter.GetSpans() {
+		if s.Name == "TestT_Eventf/adds_formatted_event" {
 			targetSpan = s
 
 			break
 		}
 	}
 
-	fatalFound := false
+	found := false
 
 	for _, event := range targetSpan.Events {
-		if event.Name == "log" {
-			for _, attr := range event.Attributes {
-				if attr.Key == "level" && attr.Value.AsString() == "fatal" {
-					fatalFound = true
-				}
-			}
+		if event.Name == "started processing batch 3 of orders" {
+			found = true
+
+			break
 		}
 	}
 
-	if !fatalFound {
-		t.Error("expected fatal log event not found")
+	if !found {
+		t.Errorf("expected formatted event on span, got events %v", targetSpan.Events)
 	}
 }
 
-func TestT_Skip(t *testing.T) {
+func TestT_Context(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
-	exporter, sp := setupTestTracer(t)
-	mock := newMockTB("TestT_Skip")
+	_, sp := setupTestTracer(t)
 
-	// when
-	st, err := sp.New(mock)
+	st, err := sp.New(t)
 	if err != nil {
 		t.Fatalf("failed to create test: %v", err)
 	}
 
-	st.Skip("skipping test")
-	mock.runCleanups()
+	// when
+	ctx := st.Context()
 
 	// then
-	spans := exporter.GetSpans()
+	if ctx == nil {
+		t.Error("expected non-nil context")
+	}
+}
 
-	var targetSpan tracetest.SpanStub
+func TestT_Span(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
 
-	for _, s := range spans {
-		if s.Name == "TestT_Skip" {
-			targetSpan = s
+	// given
+	_, sp := setupTestTracer(t)
 
-			break
-		}
+	st, err := sp.New(t)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
 	}
 
-	skipFound := false
-
-	for _, event := range targetSpan.Events {
-		if event.Name == "log" {
-			for _, attr := range event.Attributes {
-				if attr.Key == "level" && attr.Value.AsString() == "skip" {
-					skipFound = true
-				}
-			}
-		}
-	}
+	// when
+	span := st.Span()
 
-	if !skipFound {
-		t.Error("expected skip log event not found")
+	// then
+	if span == nil {
+		t.Error("expected non-nil span")
 	}
 
-	if !mock.skipped {
-		t.Error("expected mock to be marked as skipped")
+	if !span.SpanContext().IsValid() {
+		t.Error("expected valid span context")
 	}
 }
 
-func TestT_Skipf(t *testing.T) {
+func TestT_TraceIDAndSpanID(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
-	exporter, sp := setupTestTracer(t)
-	mock := newMockTB("TestT_Skipf")
+	_, sp := setupTestTracer(t)
 
-	// when
-	st, err := sp.New(mock)
+	st, err := sp.New(t)
 	if err != nil {
 		t.Fatalf("failed to create test: %v", err)
 	}
 
-	st.Skipf("skipping: %s", "reason")
-	mock.runCleanups()
+	// when
+	traceID := st.TraceID()
+	spanID := st.SpanID()
 
 	// then
-	spans := exporter.GetSpans()
+	if traceID == "" || traceID != st.Span().SpanContext().TraceID().String() {
+		t.Errorf("expected trace id of the test span, got %q", traceID)
+	}
 
-	var targetSpan tracetest.SpanStub
+	if spanID == "" || spanID != st.Span().SpanContext().SpanID().String() {
+		t.Errorf("expected span id of the test span, got %q", spanID)
+	}
+}
 
-	for _, s := range spans {
-		if s.Name == "TestT_Skipf" {
-			targetSpan = s
+func TestT_TraceIDAndSpanIDInvalidSpan(t *testing.T) {
+	t.Parallel()
 
-			break
-		}
+	// given
+	sp, err := spectra.Init(spectra.WithDisabled())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	skipFound := false
+	st, err := sp.New(t)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
 
-	for _, event := range targetSpan.Events {
-		if event.Name == "log" {
-			for _, attr := range event.Attributes {
-				if attr.Key == "level" && attr.Value.AsString() == "skip" {
-					skipFound = true
-				}
-			}
-		}
+	// when/then
+	if got := st.TraceID(); got != "" {
+		t.Errorf("expected empty trace id, got %q", got)
 	}
 
-	if !skipFound {
-		t.Error("expected skip log event not found")
+	if got := st.SpanID(); got != "" {
+		t.Errorf("expected empty span id, got %q", got)
 	}
 }
 
-func TestT_Parallel(t *testing.T) {
+func TestT_Run(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
-	_, sp := setupTestTracer(t)
+	exporter, sp := setupTestTracer(t)
 
-	// when - run in subtest with Parallel.
-	t.Run("parallel_test", func(innerT *testing.T) {
+	// when - run parent and subtest.
+	t.Run("parent", func(innerT *testing.T) {
 		st, err := sp.New(innerT)
 		if err != nil {
 			innerT.Fatalf("failed to create test: %v", err)
 		}
 
-		st.Parallel()
-		st.Log("running in parallel")
+		st.Run("subtest", func(subST *spectra.T) {
+			subST.Log("subtest message")
+		})
 	})
 
-	// then - test passes if no panic occurred.
-}
+	// then
+	spans := exporter.GetSpans()
+	if len(spans) < 2 {
+		t.Fatalf("expected at least 2 spans (parent + subtest), got %d", len(spans))
+	}
 
-func TestInit(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+	// Verify both parent and child spans exist.
+	parentFound := false
+	childFound := false
 
-	// given/when
-	sp, err := spectra.Init(
-		spectra.WithServiceName("test-service"),
-		spectra.WithEndpoint("grpc://localhost:4317"),
-		spectra.WithInsecure(),
-	)
-	// then - should return a valid Spectra instance.
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	for _, s := range spans {
+		if s.Name == "TestT_Run/parent" {
+			parentFound = true
+		}
+
+		if s.Name == "TestT_Run/parent/subtest" {
+			childFound = true
+		}
 	}
 
-	if sp == nil {
-		t.Error("expected non-nil Spectra instance")
+	if !parentFound {
+		t.Error("expected parent span not found")
 	}
 
-	// Cleanup.
-	sp.Shutdown()
+	if !childFound {
+		t.Error("expected child span not found")
+	}
 }
 
-func TestInit_HTTP(t *testing.T) {
+// *spectra.T must remain usable wherever a testing.TB is expected.
+var _ testing.TB = (*spectra.T)(nil)
+
+func TestT_InheritedMethods(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
-	// given/when
-	sp, err := spectra.Init(
-		spectra.WithServiceName("test-service"),
-		spectra.WithEndpoint("http://localhost:4318"),
-	)
-	// then - should return a valid Spectra instance.
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
+	// given
+	_, sp := setupTestTracer(t)
 
-	if sp == nil {
-		t.Error("expected non-nil Spectra instance")
-	}
+	t.Run("parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
 
-	sp.Shutdown()
-}
+		// when - methods delegate to the embedded testing.TB.
+		dir := st.TempDir()
+		st.Setenv("SPECTRA_INHERITED", "yes")
 
-func TestInit_HTTPS(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+		// then
+		if dir == "" {
+			innerT.Error("expected non-empty temp dir")
+		}
 
-	// given/when
-	sp, err := spectra.Init(
+		if got := os.Getenv("SPECTRA_INHERITED"); got != "yes" {
+			innerT.Errorf("expected env var 'yes', got %q", got)
+		}
+
+		st.Run("subtest", func(subST *spectra.T) {
+			if subST.TempDir() == "" {
+				subST.Error("expected non-empty temp dir in subtest")
+			}
+
+			if subST.Failed() {
+				subST.Error("expected subtest not to be failed")
+			}
+		})
+	})
+}
+
+func TestT_Deadline(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	wantDeadline, wantOK := t.Deadline()
+
+	var (
+		gotDeadline time.Time
+		gotOK       bool
+	)
+
+	// when
+	t.Run("parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Run("subtest", func(subST *spectra.T) {
+			gotDeadline, gotOK = subST.Deadline()
+		})
+	})
+
+	// then
+	if gotOK != wantOK || !gotDeadline.Equal(wantDeadline) {
+		t.Errorf("expected deadline (%v, %v), got (%v, %v)", wantDeadline, wantOK, gotDeadline, gotOK)
+	}
+
+	var attr attribute.Value
+
+	for _, s := range exporter.GetSpans() {
+		if s.Name != "TestT_Deadline/parent/subtest" {
+			continue
+		}
+
+		for _, a := range s.Attributes {
+			if a.Key == "test.deadline" {
+				attr = a.Value
+			}
+		}
+	}
+
+	if !wantOK {
+		if attr.Type() != attribute.INVALID {
+			t.Errorf("expected no test.deadline attribute without a timeout, got %q", attr.AsString())
+		}
+
+		return
+	}
+
+	if got, want := attr.AsString(), wantDeadline.Format(time.RFC3339Nano); got != want {
+		t.Errorf("expected test.deadline=%q, got %q", want, got)
+	}
+}
+
+func TestT_Deadline_NotTestingT(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	_, sp := setupTestTracer(t)
+	mock := newMockTB("deadline-mock")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	deadline, ok := st.Deadline()
+	mock.runCleanups()
+
+	// then
+	if ok || !deadline.IsZero() {
+		t.Errorf("expected no deadline for non-*testing.T, got (%v, %v)", deadline, ok)
+	}
+}
+
+func TestT_TempDir(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	tests := []struct {
+		name     string
+		tempDir  string
+		wantAttr bool
+	}{
+		{name: "records_path", tempDir: "/tmp/spectra-test", wantAttr: true},
+		{name: "empty_path_skipped", tempDir: "", wantAttr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			exporter, sp := setupTestTracer(t)
+			mock := newMockTB(tt.name)
+			mock.tempDir = tt.tempDir
+
+			st, err := sp.New(mock)
+			if err != nil {
+				t.Fatalf("failed to create test: %v", err)
+			}
+
+			// when
+			dir := st.TempDir()
+			mock.runCleanups()
+
+			// then
+			if dir != tt.tempDir {
+				t.Errorf("expected temp dir %q, got %q", tt.tempDir, dir)
+			}
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+
+			found := false
+
+			for _, attr := range spans[0].Attributes {
+				if attr.Key == "test.tempdir" {
+					found = true
+
+					if attr.Value.AsString() != tt.tempDir {
+						t.Errorf("expected test.tempdir=%q, got %q", tt.tempDir, attr.Value.AsString())
+					}
+				}
+			}
+
+			if found != tt.wantAttr {
+				t.Errorf("expected test.tempdir attribute present=%v, got %v", tt.wantAttr, found)
+			}
+		})
+	}
+}
+
+func TestT_Setenv(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("setenv")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	st.Setenv("SPECTRA_SECRET", "hunter2")
+	mock.runCleanups()
+
+	// then
+	if got := mock.env["SPECTRA_SECRET"]; got != "hunter2" {
+		t.Errorf("expected underlying Setenv to receive 'hunter2', got %q", got)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	attrs := eventAttributes(spans[0], "setenv")
+	if attrs == nil {
+		t.Fatal("expected setenv event")
+	}
+
+	if got := attrs["env.key"].AsString(); got != "SPECTRA_SECRET" {
+		t.Errorf("expected env.key='SPECTRA_SECRET', got %q", got)
+	}
+
+	for key, value := range attrs {
+		if value.Emit() == "hunter2" {
+			t.Errorf("expected env value not to be recorded, found in %s", key)
+		}
+	}
+}
+
+func TestT_Run_RecordsMetrics(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	_, sp := setupTestTracer(t)
+	reader := sdkmetric.NewManualReader()
+	spectra.UseMetricReader(t, sp, reader)
+
+	// when
+	t.Run("parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Run("subtest", func(_ *spectra.T) {})
+	})
+
+	// then - both the parent and the subtest are counted.
+	names := countedTestNames(t, reader, "test.count")
+
+	for _, want := range []string{"TestT_Run_RecordsMetrics/parent", "TestT_Run_RecordsMetrics/parent/subtest"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("expected test.count data point for %q, got %v", want, names)
+		}
+	}
+}
+
+func TestT_RecordsFailures(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	_, sp := setupTestTracer(t)
+	reader := sdkmetric.NewManualReader()
+	spectra.UseMetricReader(t, sp, reader)
+
+	passing := newMockTB("passing")
+	failing := newMockTB("failing")
+	skipped := newMockTB("skipped")
+
+	for _, mock := range []*mockTB{passing, failing, skipped} {
+		_, err := sp.New(mock)
+		if err != nil {
+			t.Fatalf("failed to create test: %v", err)
+		}
+	}
+
+	// when
+	failing.failed = true
+	skipped.skipped = true
+
+	for _, mock := range []*mockTB{passing, failing, skipped} {
+		mock.runCleanups()
+	}
+
+	// then - every test is counted, but only the failing one is a failure.
+	if got := countedTestNames(t, reader, "test.count"); len(got) != 3 {
+		t.Errorf("expected 3 test.count data points, got %v", got)
+	}
+
+	if got := countedTestNames(t, reader, "test.failures"); !slices.Equal(got, []string{"failing"}) {
+		t.Errorf("expected test.failures only for 'failing', got %v", got)
+	}
+}
+
+func TestT_RecordsActiveTests(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	_, sp := setupTestTracer(t)
+	reader := sdkmetric.NewManualReader()
+	spectra.UseMetricReader(t, sp, reader)
+
+	var (
+		mu   sync.Mutex
+		peak int64
+	)
+
+	// when
+	t.Run("parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		for _, name := range []string{"a", "b", "c"} {
+			st.Run(name, func(subST *spectra.T) {
+				subST.Parallel()
+
+				active := activeTests(subST, reader)
+
+				mu.Lock()
+				peak = max(peak, active)
+				mu.Unlock()
+			})
+		}
+	})
+
+	// then - the parent and at least one subtest were running together, and
+	// every test was subtracted again on completion.
+	if peak < 2 {
+		t.Errorf("expected at least 2 active tests while subtests ran, got peak %d", peak)
+	}
+
+	if got := activeTests(t, reader); got != 0 {
+		t.Errorf("expected no active tests after completion, got %d", got)
+	}
+}
+
+func TestT_RecordValue(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	_, sp := setupTestTracer(t)
+	reader := sdkmetric.NewManualReader()
+	spectra.UseMetricReader(t, sp, reader)
+
+	// when - parallel subtests record to the same lazily created histogram.
+	t.Run("parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		for _, name := range []string{"a", "b"} {
+			st.Run(name, func(subST *spectra.T) {
+				subST.Parallel()
+				subST.RecordValue("rows.processed", 42, attribute.String("table", "orders"))
+			})
+		}
+	})
+
+	// then
+	var rm metricdata.ResourceMetrics
+
+	err := reader.Collect(context.Background(), &rm)
+	if err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	var points []metricdata.HistogramDataPoint[float64]
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "rows.processed" {
+				continue
+			}
+
+			hist, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok {
+				t.Fatalf("expected rows.processed to be Histogram[float64], got %T", m.Data)
+			}
+
+			points = append(points, hist.DataPoints...)
+		}
+	}
+
+	if len(points) != 2 {
+		t.Fatalf("expected 2 data points, got %d", len(points))
+	}
+
+	for _, dp := range points {
+		name, _ := dp.Attributes.Value("test.name")
+		table, _ := dp.Attributes.Value("table")
+
+		if !strings.HasPrefix(name.AsString(), "TestT_RecordValue/parent/") {
+			t.Errorf("expected subtest name, got %q", name.AsString())
+		}
+
+		if table.AsString() != "orders" {
+			t.Errorf("expected table 'orders', got %q", table.AsString())
+		}
+
+		if dp.Count != 1 || dp.Sum != 42 {
+			t.Errorf("expected a single value of 42, got count %d sum %v", dp.Count, dp.Sum)
+		}
+	}
+}
+
+func TestT_Measure(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	reader := sdkmetric.NewManualReader()
+	spectra.UseMetricReader(t, sp, reader)
+
+	// when
+	t.Run("measures", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Measure("import", func() {
+			time.Sleep(10 * time.Millisecond)
+		})
+	})
+
+	// then
+	var targetSpan tracetest.SpanStub
+
+	for _, s := range exporter.GetSpans() {
+		if s.Name == "TestT_Measure/measures" {
+			targetSpan = s
+
+			break
+		}
+	}
+
+	var events []string
+
+	for _, event := range targetSpan.Events {
+		events = append(events, event.Name)
+	}
+
+	if !slices.Equal(events, []string{"import.start", "import.end"}) {
+		t.Errorf("expected import.start and import.end events, got %v", events)
+	}
+
+	var duration float64
+
+	for _, attr := range targetSpan.Attributes {
+		if attr.Key == "import.duration_ms" {
+			duration = attr.Value.AsFloat64()
+		}
+	}
+
+	if duration < 10 {
+		t.Errorf("expected import.duration_ms of at least 10, got %v", duration)
+	}
+
+	var rm metricdata.ResourceMetrics
+
+	err := reader.Collect(context.Background(), &rm)
+	if err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	var points []metricdata.HistogramDataPoint[float64]
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "import.duration_ms" {
+				continue
+			}
+
+			hist, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok {
+				t.Fatalf("expected import.duration_ms to be Histogram[float64], got %T", m.Data)
+			}
+
+			points = append(points, hist.DataPoints...)
+		}
+	}
+
+	if len(points) != 1 {
+		t.Fatalf("expected 1 data point, got %d", len(points))
+	}
+
+	if points[0].Count != 1 || points[0].Sum != duration {
+		t.Errorf("expected a single value of %v, got count %d sum %v", duration, points[0].Count, points[0].Sum)
+	}
+}
+
+func TestT_RecordValue_InvalidName(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	reader := sdkmetric.NewManualReader()
+
+	var logged []string
+
+	sp, err := spectra.NewWithMetricReader(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithLogger(func(format string, args ...any) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		}),
+	), reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("TestT_RecordValue_InvalidName")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	st.RecordValue("rows processed", 1)
+	st.RecordValue("rows processed", 2)
+	st.Measure("import fixtures", func() {})
+
+	mock.runCleanups()
+
+	// then
+	if mock.failed {
+		t.Error("expected an invalid instrument name not to fail the test")
+	}
+
+	if len(logged) != 2 {
+		t.Errorf("expected one logged error per invalid name, got %v", logged)
+	}
+
+	var rm metricdata.ResourceMetrics
+
+	err = reader.Collect(context.Background(), &rm)
+	if err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	var count uint64
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			hist, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok || m.Name != "rows processed" {
+				continue
+			}
+
+			for _, dp := range hist.DataPoints {
+				count += dp.Count
+			}
+		}
+	}
+
+	if count != 2 {
+		t.Errorf("expected 2 values recorded to %q, got %d", "rows processed", count)
+	}
+}
+
+func TestSpectra_Meter(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	t.Run("shares_provider", func(t *testing.T) {
+		// given
+		reader := sdkmetric.NewManualReader()
+
+		sp, err := spectra.NewWithMetricReader(spectra.NewConfig(
+			spectra.WithServiceName("test"),
+			spectra.WithEndpoint("grpc://localhost:4317"),
+		), reader)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer func() {
+			err := sp.Shutdown()
+			if err != nil {
+				t.Errorf("unexpected shutdown error: %v", err)
+			}
+		}()
+
+		counter, err := sp.Meter().Int64Counter("custom.count")
+		if err != nil {
+			t.Fatalf("failed to create counter: %v", err)
+		}
+
+		// when
+		counter.Add(context.Background(), 3)
+
+		// then
+		if got := countedTestNames(t, reader, "custom.count"); len(got) != 1 {
+			t.Errorf("expected 1 custom.count data point, got %v", got)
+		}
+	})
+
+	t.Run("metrics_disabled", func(t *testing.T) {
+		// given
+		_, sp := setupTestTracer(t)
+
+		// when
+		counter, err := sp.Meter().Int64Counter("custom.count")
+		if err != nil {
+			t.Fatalf("failed to create counter: %v", err)
+		}
+
+		// then - recording on the no-op meter must not panic.
+		counter.Add(context.Background(), 1)
+	})
+}
+
+func TestT_DurationBuckets(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	tests := []struct {
+		name       string
+		opts       []spectra.Option
+		wantBounds []float64
+	}{
+		{name: "default", wantBounds: []float64{0.001, 0.01, 0.1, 1, 10}},
+		{
+			name:       "custom",
+			opts:       []spectra.Option{spectra.WithDurationBuckets([]float64{60, 120})},
+			wantBounds: []float64{60, 120},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			_, sp := setupTestTracer(t)
+			reader := sdkmetric.NewManualReader()
+			spectra.UseMetricReader(t, sp, reader, tt.opts...)
+
+			// when
+			t.Run("timed", func(innerT *testing.T) {
+				_, err := sp.New(innerT)
+				if err != nil {
+					innerT.Fatalf("failed to create test: %v", err)
+				}
+			})
+
+			// then - the fast test lands in the first bucket.
+			dp := durationHistogram(t, reader, t.Name()+"/timed")
+
+			if !slices.Equal(dp.Bounds, tt.wantBounds) {
+				t.Errorf("expected bounds %v, got %v", tt.wantBounds, dp.Bounds)
+			}
+
+			if dp.Count != 1 || len(dp.BucketCounts) == 0 || dp.BucketCounts[0] != 1 {
+				t.Errorf("expected a single recording in the first bucket, got %v", dp.BucketCounts)
+			}
+		})
+	}
+}
+
+func TestWithMetricTemporality(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        []spectra.Option
+		wantCounter metricdata.Temporality
+	}{
+		{name: "default", wantCounter: metricdata.CumulativeTemporality},
+		{
+			name:        "cumulative",
+			opts:        []spectra.Option{spectra.WithMetricTemporality(metricdata.CumulativeTemporality)},
+			wantCounter: metricdata.CumulativeTemporality,
+		},
+		{
+			name:        "delta",
+			opts:        []spectra.Option{spectra.WithMetricTemporality(metricdata.DeltaTemporality)},
+			wantCounter: metricdata.DeltaTemporality,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			selector := spectra.TemporalitySelector(spectra.NewConfig(tt.opts...))
+
+			// when/then
+			for _, kind := range []sdkmetric.InstrumentKind{
+				sdkmetric.InstrumentKindCounter,
+				sdkmetric.InstrumentKindHistogram,
+			} {
+				if got := selector(kind); got != tt.wantCounter {
+					t.Errorf("expected %v temporality for %v, got %v", tt.wantCounter, kind, got)
+				}
+			}
+
+			if got := selector(sdkmetric.InstrumentKindUpDownCounter); got != metricdata.CumulativeTemporality {
+				t.Errorf("expected up-down counters to stay cumulative, got %v", got)
+			}
+		})
+	}
+}
+
+func TestInit_WithMetricViews(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	_, sp := setupTestTracer(t)
+	reader := sdkmetric.NewManualReader()
+	spectra.UseMetricReader(t, sp, reader, spectra.WithMetricViews(sdkmetric.NewView(
+		sdkmetric.Instrument{Name: "test.*"},
+		sdkmetric.Stream{AttributeFilter: attribute.NewDenyKeysFilter("test.name")},
+	)))
+
+	// when
+	t.Run("viewed", func(innerT *testing.T) {
+		_, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+	})
+
+	// then
+	names := countedTestNames(t, reader, "test.count")
+	if !slices.Equal(names, []string{""}) {
+		t.Errorf("expected a single test.count data point without test.name, got %q", names)
+	}
+}
+
+func TestInit_WithMetricNameCardinalityLimit(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	reader := sdkmetric.NewManualReader()
+
+	sp, err := spectra.NewWithMetricReader(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithMetricNameCardinalityLimit(3),
+	), reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	parent := newMockTB("table")
+
+	st, err := sp.New(parent)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	for i := range 20 {
+		child := newMockTB(fmt.Sprintf("table/case-%d", i))
+		spectra.NewSubtest(st, child)
+		child.runCleanups()
+	}
+
+	parent.runCleanups()
+
+	// then
+	names := countedTestNames(t, reader, "test.count")
+
+	distinct := make(map[string]bool)
+	for _, name := range names {
+		distinct[name] = true
+	}
+
+	if len(distinct) > 4 {
+		t.Errorf("expected at most 3 test names plus %q, got %v", "other", names)
+	}
+
+	if !distinct["other"] {
+		t.Errorf("expected overflowing tests to be recorded as %q, got %v", "other", names)
+	}
+}
+
+func TestInit_WithoutTestNameMetricAttribute(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	reader := sdkmetric.NewManualReader()
+
+	sp, err := spectra.NewWithMetricReader(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithoutTestNameMetricAttribute(),
+	), reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("TestInit_WithoutTestNameMetricAttribute")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	st.Error("boom")
+	mock.runCleanups()
+
+	// then
+	var rm metricdata.ResourceMetrics
+
+	err = reader.Collect(context.Background(), &rm)
+	if err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	var sets []attribute.Set
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					sets = append(sets, dp.Attributes)
+				}
+			case metricdata.Sum[int64]:
+				if m.Name == "test.active" {
+					continue
+				}
+
+				for _, dp := range data.DataPoints {
+					sets = append(sets, dp.Attributes)
+				}
+			}
+		}
+	}
+
+	if len(sets) != 3 {
+		t.Fatalf("expected duration, count and failures data points, got %d", len(sets))
+	}
+
+	for _, set := range sets {
+		if set.HasValue("test.name") {
+			t.Errorf("expected no test.name attribute, got %v", set.ToSlice())
+		}
+	}
+}
+
+func TestInit_ConflictingSignalConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    []spectra.Option
+		wantMsg string
+	}{
+		{
+			name:    "span processor without traces",
+			opts:    []spectra.Option{spectra.WithoutTraces(), spectra.WithSpanProcessor(tracetest.NewSpanRecorder())},
+			wantMsg: "WithoutTraces with WithSpanProcessor",
+		},
+		{
+			name:    "traces endpoint without traces",
+			opts:    []spectra.Option{spectra.WithoutTraces(), spectra.WithTracesEndpoint("grpc://localhost:4317")},
+			wantMsg: "WithoutTraces with WithTracesEndpoint",
+		},
+		{
+			name: "metric reader without metrics",
+			opts: []spectra.Option{
+				spectra.WithoutMetrics(),
+				spectra.WithMetricReader(sdkmetric.NewManualReader()),
+			},
+			wantMsg: "WithoutMetrics with WithMetricReader",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			opts := append([]spectra.Option{
+				spectra.WithServiceName("test"),
+				spectra.WithEndpoint("grpc://localhost:4317"),
+				spectra.WithGlobalProviders(false),
+			}, tt.opts...)
+
+			// when
+			_, err := spectra.Init(opts...)
+
+			// then
+			if !errors.Is(err, spectra.ErrConflictingSignalConfig) {
+				t.Fatalf("expected ErrConflictingSignalConfig, got %v", err)
+			}
+
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("expected error to mention %q, got %q", tt.wantMsg, err.Error())
+			}
+		})
+	}
+}
+
+func TestInit_MetricViewsWithoutMetrics(t *testing.T) {
+	t.Parallel()
+
+	// given/when
+	_, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithMetricViews(sdkmetric.NewView(
+			sdkmetric.Instrument{Name: "test.count"},
+			sdkmetric.Stream{Name: "spectra.test.count"},
+		)),
+		spectra.WithoutMetrics(),
+		spectra.WithGlobalProviders(false),
+	)
+
+	// then
+	if !errors.Is(err, spectra.ErrMetricViewsWithoutMetrics) {
+		t.Errorf("expected ErrMetricViewsWithoutMetrics, got %v", err)
+	}
+}
+
+func TestT_StartSpan(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+
+	// when
+	t.Run("creates_child_span", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		ctx, span := st.StartSpan("custom-operation")
+		span.End()
+
+		if ctx == nil {
+			innerT.Error("expected non-nil context")
+		}
+	})
+
+	// then
+	spans := exporter.GetSpans()
+	found := false
+
+	for _, s := range spans {
+		if s.Name == "custom-operation" {
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		t.Error("expected custom span not found")
+	}
+}
+
+func TestT_StartSpanFrom(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+
+	// when
+	t.Run("uses_context_parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Step("act", func(ctx context.Context) {
+			_, span := st.StartSpanFrom(ctx, "nested-operation")
+			span.End()
+		})
+	})
+
+	// then
+	spans := make(map[string]tracetest.SpanStub)
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+
+	step, ok := spans["TestT_StartSpanFrom/uses_context_parent/act"]
+	if !ok {
+		t.Fatal("expected step span")
+	}
+
+	nested, ok := spans["nested-operation"]
+	if !ok {
+		t.Fatal("expected nested span")
+	}
+
+	if nested.Parent.SpanID() != step.SpanContext.SpanID() {
+		t.Errorf("expected nested span parent %s, got %s", step.SpanContext.SpanID(), nested.Parent.SpanID())
+	}
+}
+
+func TestT_SetBaggage(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	_, sp := setupTestTracer(t)
+
+	st, err := sp.New(t)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	member, err := baggage.NewMember("test.correlation_id", "abc123")
+	if err != nil {
+		t.Fatalf("failed to create baggage member: %v", err)
+	}
+
+	// when
+	st.SetBaggage(member)
+
+	ctx, span := st.StartSpan("child")
+	defer span.End()
+
+	// then - baggage is readable from T and present on derived contexts.
+	if got := st.Baggage().Member("test.correlation_id").Value(); got != "abc123" {
+		t.Errorf("expected baggage value 'abc123' on T, got %q", got)
+	}
+
+	if got := baggage.FromContext(ctx).Member("test.correlation_id").Value(); got != "abc123" {
+		t.Errorf("expected baggage value 'abc123' on child context, got %q", got)
+	}
+}
+
+func TestT_Setup(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+
+	// when
+	t.Run("runs_setup", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		setupCalled := false
+
+		st.Setup(func(_ context.Context) {
+			setupCalled = true
+		})
+
+		if !setupCalled {
+			innerT.Error("expected setup function to be called")
+		}
+	})
+
+	// then
+	spans := exporter.GetSpans()
+	found := false
+
+	for _, s := range spans {
+		if s.Name == "TestT_Setup/runs_setup/setup" {
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		t.Error("expected setup span not found")
+	}
+}
+
+func TestT_Teardown(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	teardownCalled := false
+
+	// when
+	t.Run("runs_teardown", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Teardown(func(_ context.Context) {
+			teardownCalled = true
+		})
+
+		// Teardown hasn't been called yet.
+		if teardownCalled {
+			innerT.Error("teardown should not be called until cleanup")
+		}
+	})
+
+	// then - after subtest completes, teardown should have run.
+	if !teardownCalled {
+		t.Error("expected teardown to be called after test cleanup")
+	}
+
+	spans := exporter.GetSpans()
+	found := false
+
+	for _, s := range spans {
+		if s.Name == "TestT_Teardown/runs_teardown/teardown" {
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		t.Error("expected teardown span not found")
+	}
+}
+
+func TestT_Teardown_AfterFatal(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	_, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_Teardown_AfterFatal")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	var teardownErr, cleanupErr error
+
+	teardownCalled := false
+
+	st.Teardown(func(ctx context.Context) {
+		teardownCalled = true
+		teardownErr = ctx.Err()
+	})
+	st.TracedCleanup("stop-server", func(ctx context.Context) {
+		cleanupErr = ctx.Err()
+	})
+
+	// when
+	st.Fatal("boom")
+	mock.runCleanups()
+
+	// then
+	if !teardownCalled {
+		t.Fatal("expected teardown to be called")
+	}
+
+	if teardownErr != nil {
+		t.Errorf("expected a live teardown context after Fatal, got %v", teardownErr)
+	}
+
+	if cleanupErr != nil {
+		t.Errorf("expected a live cleanup context after Fatal, got %v", cleanupErr)
+	}
+}
+
+func TestNew_SpanStartTimeMatchesTestStart(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestNew_SpanStartTimeMatchesTestStart")
+	child := newMockTB("TestNew_SpanStartTimeMatchesTestStart/sub")
+
+	// when
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	sub := spectra.NewSubtest(st, child)
+
+	child.runCleanups()
+	mock.runCleanups()
+
+	// then
+	starts := map[string]time.Time{
+		"TestNew_SpanStartTimeMatchesTestStart":     spectra.StartTime(st),
+		"TestNew_SpanStartTimeMatchesTestStart/sub": spectra.StartTime(sub),
+	}
+
+	for _, s := range exporter.GetSpans() {
+		want, ok := starts[s.Name]
+		if !ok {
+			continue
+		}
+
+		if !s.StartTime.Equal(want) {
+			t.Errorf("expected span %q to start at %v, got %v", s.Name, want, s.StartTime)
+		}
+
+		delete(starts, s.Name)
+	}
+
+	for name := range starts {
+		t.Errorf("expected span %q not found", name)
+	}
+}
+
+func TestNew_CPUAttributes(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestNew_CPUAttributes")
+
+	// when
+	_, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	mock.runCleanups()
+
+	// then
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	attrs := attribute.NewSet(spans[0].Attributes...)
+
+	for _, key := range []attribute.Key{"host.num_cpu", "go.max_procs"} {
+		value, ok := attrs.Value(key)
+		if !ok || value.Type() != attribute.INT64 || value.AsInt64() <= 0 {
+			t.Errorf("expected positive integer %s attribute, got %v", key, value.Emit())
+		}
+	}
+}
+
+func TestNew_TestDurationAttribute(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+
+	var elapsed time.Duration
+
+	// when
+	t.Run("timed", func(innerT *testing.T) {
+		start := time.Now()
+
+		// Registered first so it runs after spectra's cleanup.
+		innerT.Cleanup(func() { elapsed = time.Since(start) })
+
+		_, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	})
+
+	// then
+	var (
+		duration float64
+		found    bool
+	)
+
+	for _, s := range exporter.GetSpans() {
+		if s.Name != "TestNew_TestDurationAttribute/timed" {
+			continue
+		}
+
+		for _, attr := range s.Attributes {
+			if attr.Key == "test.duration_ms" {
+				duration = attr.Value.AsFloat64()
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		t.Fatal("expected test.duration_ms attribute on test span")
+	}
+
+	if duration < 5 {
+		t.Errorf("expected test.duration_ms of at least 5, got %v", duration)
+	}
+
+	measured := float64(elapsed) / float64(time.Millisecond)
+	if duration > measured {
+		t.Errorf("expected test.duration_ms %v to be at most the measured %v", duration, measured)
+	}
+}
+
+func TestT_SetupTeardown_PhaseDuration(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+
+	// when
+	t.Run("fixtures", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Setup(func(_ context.Context) {
+			time.Sleep(time.Millisecond)
+		})
+		st.Teardown(func(_ context.Context) {
+			time.Sleep(time.Millisecond)
+		})
+	})
+
+	// then
+	durations := make(map[string]float64)
+
+	for _, s := range exporter.GetSpans() {
+		for _, attr := range s.Attributes {
+			if attr.Key == "phase.duration_ms" {
+				durations[s.Name] = attr.Value.AsFloat64()
+			}
+		}
+	}
+
+	for _, name := range []string{
+		"TestT_SetupTeardown_PhaseDuration/fixtures/setup",
+		"TestT_SetupTeardown_PhaseDuration/fixtures/teardown",
+	} {
+		duration, ok := durations[name]
+		if !ok {
+			t.Errorf("expected phase.duration_ms attribute on span %q", name)
+
+			continue
+		}
+
+		if duration <= 0 {
+			t.Errorf("expected positive phase.duration_ms on span %q, got %v", name, duration)
+		}
+	}
+}
+
+func TestT_TracedCleanup(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+
+	var cleanups []string
+
+	// when
+	t.Run("runs_cleanups", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.TracedCleanup("first", func(_ context.Context) {
+			cleanups = append(cleanups, "first")
+		})
+		st.TracedCleanup("second", func(_ context.Context) {
+			cleanups = append(cleanups, "second")
+		})
+
+		if len(cleanups) != 0 {
+			innerT.Error("cleanups should not run until the test completes")
+		}
+	})
+
+	// then
+	if len(cleanups) != 2 {
+		t.Fatalf("expected 2 cleanups to run, got %v", cleanups)
+	}
+
+	phases := make(map[string]string)
+
+	for _, s := range exporter.GetSpans() {
+		for _, attr := range s.Attributes {
+			if attr.Key == "test.phase" {
+				phases[s.Name] = attr.Value.AsString()
+			}
+		}
+	}
+
+	for _, name := range []string{
+		"TestT_TracedCleanup/runs_cleanups/cleanup/first",
+		"TestT_TracedCleanup/runs_cleanups/cleanup/second",
+	} {
+		if phases[name] != "cleanup" {
+			t.Errorf("expected cleanup span %q with phase cleanup, got %q", name, phases[name])
+		}
+	}
+}
+
+func TestT_Step(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+
+	var actBaggage string
+
+	// when
+	t.Run("checkout", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Step("arrange", func(_ context.Context) {
+			member, memberErr := baggage.NewMember("cart.id", "42")
+			if memberErr != nil {
+				innerT.Fatalf("failed to create baggage member: %v", memberErr)
+			}
+
+			st.SetBaggage(member)
+		})
+		st.Step("act", func(ctx context.Context) {
+			actBaggage = baggage.FromContext(ctx).Member("cart.id").Value()
+		})
+		st.Step("assert", func(_ context.Context) {})
+	})
+
+	// then
+	if actBaggage != "42" {
+		t.Errorf("expected later step to see baggage %q, got %q", "42", actBaggage)
+	}
+
+	spans := exporter.GetSpans()
+
+	var testSpanID trace.SpanID
+
+	for _, s := range spans {
+		if s.Name == "TestT_Step/checkout" {
+			testSpanID = s.SpanContext.SpanID()
+		}
+	}
+
+	for _, step := range []string{"arrange", "act", "assert"} {
+		name := "TestT_Step/checkout/" + step
+		found := false
+
+		for _, s := range spans {
+			if s.Name != name {
+				continue
+			}
+
+			found = true
+
+			if s.Parent.SpanID() != testSpanID {
+				t.Errorf("expected step span %q to be a child of the test span", name)
+			}
+		}
+
+		if !found {
+			t.Errorf("expected step span %q not found", name)
+		}
+	}
+}
+
+func TestT_Step_Panic(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_Step_Panic")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	func() {
+		defer func() { _ = recover() }()
+
+		st.Step("boom", func(_ context.Context) {
+			panic("boom")
+		})
+	}()
+
+	mock.runCleanups()
+
+	// then
+	for _, s := range exporter.GetSpans() {
+		if s.Name == "TestT_Step_Panic/boom" {
+			if s.Status.Code != codes.Error {
+				t.Errorf("expected step span status Error, got %v", s.Status.Code)
+			}
+
+			return
+		}
+	}
+
+	t.Error("expected step span not found")
+}
+
+func TestT_SpanStatus_Pass(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+
+	// when - run a passing test.
+	t.Run("passing", func(innerT *testing.T) {
+		_, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+		// Test passes without any errors.
+	})
+
+	// then
+	spans := exporter.GetSpans()
+	found := false
+
+	for _, s := range spans {
+		if s.Name == "TestT_SpanStatus_Pass/passing" && s.Status.Code == codes.Ok {
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		t.Error("expected span with Ok status not found")
+	}
+}
+
+func TestT_Debugf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		opts      []spectra.Option
+		wantEvent bool
+	}{
+		{name: "enabled", opts: []spectra.Option{spectra.WithDebugLogs()}, wantEvent: true},
+		{name: "disabled", wantEvent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			exporter := tracetest.NewInMemoryExporter()
+			opts := append([]spectra.Option{
+				spectra.WithServiceName("test"),
+				spectra.WithEndpoint("grpc://localhost:4317"),
+			}, tt.opts...)
+
+			sp, err := spectra.NewWithExporter(spectra.NewConfig(opts...), exporter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			defer func() {
+				err := sp.Shutdown()
+				if err != nil {
+					t.Errorf("unexpected shutdown error: %v", err)
+				}
+			}()
+
+			mock := newMockTB(tt.name)
+
+			st, err := sp.New(mock)
+			if err != nil {
+				t.Fatalf("failed to create test: %v", err)
+			}
+
+			// when
+			st.Debugf("cache %s", "warm")
+			mock.runCleanups()
+
+			err = sp.Flush(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected flush error: %v", err)
+			}
+
+			// then
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+
+			attrs := eventAttributes(spans[0], "log")
+			if !tt.wantEvent {
+				if attrs != nil {
+					t.Errorf("expected no log event, got %v", attrs)
+				}
+
+				return
+			}
+
+			if attrs == nil {
+				t.Fatal("expected log event")
+			}
+
+			if got := attrs["level"].AsString(); got != "debug" {
+				t.Errorf("expected level debug, got %q", got)
+			}
+
+			if got := attrs["message"].AsString(); got != "cache warm" {
+				t.Errorf("expected message 'cache warm', got %q", got)
+			}
+		})
+	}
+}
+
+func TestSpectra_WithStdLogCapture(t *testing.T) {
+	// Tests modify the standard library logger output - cannot run in parallel.
+
+	// given
+	var output strings.Builder
+
+	previous := stdlog.Writer()
+	stdlog.SetOutput(&output)
+
+	defer stdlog.SetOutput(previous)
+
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.NewWithExporter(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithStdLogCapture(),
+	), exporter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("captured")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	stdlog.Print("raw line")
+	mock.runCleanups()
+	stdlog.Print("after test")
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	attrs := eventAttributes(spans[0], "log")
+	if attrs == nil {
+		t.Fatal("expected captured log event")
+	}
+
+	if got := attrs["message"].AsString(); !strings.HasSuffix(got, "raw line") {
+		t.Errorf("expected captured message to end with 'raw line', got %q", got)
+	}
+
+	if len(spans[0].Events) != 1 {
+		t.Errorf("expected only the line logged during the test, got %d events", len(spans[0].Events))
+	}
+
+	if got := strings.Count(output.String(), "raw line"); got != 1 {
+		t.Errorf("expected the line to be written to the log output once, got %d", got)
+	}
+
+	if stdlog.Writer() != &output {
+		t.Error("expected the previous log output to be restored")
+	}
+}
+
+func TestSpectra_WithTestEndHook(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var results []spectra.TestResult
+
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.NewWithExporter(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithTestEndHook(func(result spectra.TestResult) {
+			results = append(results, result)
+		}),
+	), exporter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	passing := newMockTB("passing")
+	failing := newMockTB("failing")
+
+	// when
+	passingST, err := sp.New(passing)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	passing.runCleanups()
+
+	failingST, err := sp.New(failing)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	failingST.Error("boom")
+	failing.runCleanups()
+
+	// then
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	want := []struct {
+		name    string
+		status  string
+		failed  bool
+		traceID string
+	}{
+		{name: "passing", status: "pass", failed: false, traceID: passingST.TraceID()},
+		{name: "failing", status: "fail", failed: true, traceID: failingST.TraceID()},
+	}
+
+	for i, w := range want {
+		got := results[i]
+
+		if got.Name != w.name || got.Status != w.status || got.Failed != w.failed {
+			t.Errorf("expected %s/%s/failed=%v, got %s/%s/failed=%v",
+				w.name, w.status, w.failed, got.Name, got.Status, got.Failed)
+		}
+
+		if got.TraceID == "" || got.TraceID != w.traceID {
+			t.Errorf("expected %s trace id %q, got %q", w.name, w.traceID, got.TraceID)
+		}
+
+		if got.Duration <= 0 {
+			t.Errorf("expected %s duration to be positive, got %v", w.name, got.Duration)
+		}
+	}
+}
+
+func TestInit_WithJUnitReport(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	var report strings.Builder
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("checkout-tests"),
+		spectra.WithSpanExporter(tracetest.NewInMemoryExporter()),
+		spectra.WithJUnitReport(&report),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Run("child", func(_ *spectra.T) {})
+	})
+
+	failing := newMockTB("failing")
+
+	st, err := sp.New(failing)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	st.Error("expected 200, got 500")
+	failing.runCleanups()
+
+	// when
+	err = sp.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	// then
+	var doc struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			TestCases []struct {
+				Name      string `xml:"name,attr"`
+				ClassName string `xml:"classname,attr"`
+				Failure   *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+
+	err = xml.Unmarshal([]byte(report.String()), &doc)
+	if err != nil {
+		t.Fatalf("failed to parse report: %v\n%s", err, report.String())
+	}
+
+	if doc.Tests != 3 || doc.Failures != 1 || len(doc.Suites) != 1 {
+		t.Fatalf("expected 3 tests with 1 failure in 1 suite, got:\n%s", report.String())
+	}
+
+	classNames := make(map[string]string)
+
+	for _, testCase := range doc.Suites[0].TestCases {
+		classNames[testCase.Name] = testCase.ClassName
+
+		if testCase.Name != "failing" {
+			continue
+		}
+
+		if testCase.Failure == nil || testCase.Failure.Message != "expected 200, got 500" {
+			t.Errorf("expected failure with the logged error, got %+v", testCase.Failure)
+		}
+	}
+
+	if got := classNames["TestInit_WithJUnitReport/parent/child"]; got != "TestInit_WithJUnitReport/parent" {
+		t.Errorf("expected subtest classname from its parent, got %q", got)
+	}
+
+	if got := classNames["failing"]; got != "checkout-tests" {
+		t.Errorf("expected top-level classname from the service name, got %q", got)
+	}
+}
+
+func TestInit_WithJUnitReport_WithoutLogs(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var report strings.Builder
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("checkout-tests"),
+		spectra.WithSpanExporter(tracetest.NewInMemoryExporter()),
+		spectra.WithJUnitReport(&report),
+		spectra.WithoutLogs(),
+		spectra.WithoutMetrics(),
+		spectra.WithGlobalProviders(false),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failing := newMockTB("failing")
+
+	st, err := sp.New(failing)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	st.Error("expected 200, got 500")
+	failing.runCleanups()
+
+	// when
+	err = sp.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	// then
+	if !strings.Contains(report.String(), `message="expected 200, got 500"`) {
+		t.Errorf("expected failure message in report without log events, got:\n%s", report.String())
+	}
+}
+
+func TestT_LogKV(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+
+	// when
+	t.Run("structured", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.LogKV("order created", attribute.String("order.id", "o-1"), attribute.Int("items", 3))
+	})
+
+	// then
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	attrs := eventAttributes(spans[0], "log")
+	if attrs == nil {
+		t.Fatal("expected log event")
+	}
+
+	if got := attrs["message"].AsString(); got != "order created" {
+		t.Errorf("expected message 'order created', got %q", got)
+	}
+
+	if got := attrs["level"].AsString(); got != "info" {
+		t.Errorf("expected level info, got %q", got)
+	}
+
+	if got := attrs["order.id"].AsString(); got != "o-1" {
+		t.Errorf("expected order.id o-1, got %q", got)
+	}
+
+	if got := attrs["items"].AsInt64(); got != 3 {
+		t.Errorf("expected items 3, got %d", got)
+	}
+}
+
+func TestT_Error(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_Error")
+
+	// when
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	st.Error("test error message")
+	st.Errorf("formatted error: %s", "details")
+	mock.runCleanups()
+
+	// then
+	spans := exporter.GetSpans()
+
+	var targetSpan tracetest.SpanStub
+
+	for _, s := range spans {
+		if s.Name == "TestT_Error" {
+			targetSpan = s
+
+			break
+		}
+	}
+
+	errorEvents := 0
+
+	for _, event := range targetSpan.Events {
+		if event.Name == "log" {
+			for _, attr := range event.Attributes {
+				if attr.Key == "level" && attr.Value.AsString() == "error" {
+					errorEvents++
+				}
+			}
+		}
+	}
+
+	if errorEvents < 2 {
+		t.Errorf("expected at least 2 error events, got %d", errorEvents)
+	}
+
+	if !mock.failed {
+		t.Error("expected mock to be marked as failed")
+	}
+}
+
+func TestT_Error_RecordsException(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	tests := []struct {
+		name        string
+		record      func(st *spectra.T)
+		wantMessage string
+	}{
+		{
+			name:        "error_argument",
+			record:      func(st *spectra.T) { st.Error("query failed:", errors.New("connection refused")) },
+			wantMessage: "connection refused",
+		},
+		{
+			name:        "formatted_message",
+			record:      func(st *spectra.T) { st.Errorf("expected %d, got %d", 1, 2) },
+			wantMessage: "expected 1, got 2",
+		},
+		{
+			name:        "fatal_message",
+			record:      func(st *spectra.T) { st.Fatal("fatal error") },
+			wantMessage: "fatal error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			exporter, sp := setupTestTracer(t)
+			mock := newMockTB(tt.name)
+
+			st, err := sp.New(mock)
+			if err != nil {
+				t.Fatalf("failed to create test: %v", err)
+			}
+
+			// when
+			tt.record(st)
+			mock.runCleanups()
+
+			// then
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+
+			found := false
+
+			for _, event := range spans[0].Events {
+				if event.Name != "exception" {
+					continue
+				}
+
+				for _, attr := range event.Attributes {
+					if attr.Key == "exception.message" && attr.Value.AsString() == tt.wantMessage {
+						found = true
+					}
+				}
+			}
+
+			if !found {
+				t.Errorf("expected exception event with message %q", tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestT_Log_ExportsLogRecords(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	spanExporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spanExporter))
+	otel.SetTracerProvider(tp)
+
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	logExporter := &memoryLogExporter{}
+
+	sp, err := spectra.NewWithLogExporter(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+	), logExporter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mock := newMockTB("export-logs")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	st.Log("hello")
+	st.Error("boom")
+	mock.runCleanups()
+
+	err = sp.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	// then
+	records := logExporter.Records()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 log records, got %d", len(records))
+	}
+
+	tests := []struct {
+		body     string
+		severity log.Severity
+		text     string
+	}{
+		{body: "hello", severity: log.SeverityInfo, text: "info"},
+		{body: "boom", severity: log.SeverityError, text: "error"},
+	}
+
+	spans := spanExporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	for i, want := range tests {
+		record := records[i]
+
+		if got := record.Body().AsString(); got != want.body {
+			t.Errorf("record %d: expected body %q, got %q", i, want.body, got)
+		}
+
+		if got := record.Severity(); got != want.severity {
+			t.Errorf("record %d: expected severity %v, got %v", i, want.severity, got)
+		}
+
+		if got := record.SeverityText(); got != want.text {
+			t.Errorf("record %d: expected severity text %q, got %q", i, want.text, got)
+		}
+
+		if got := record.TraceID(); got != spans[0].SpanContext.TraceID() {
+			t.Errorf("record %d: expected trace ID %s, got %s", i, spans[0].SpanContext.TraceID(), got)
+		}
+	}
+
+	// Span events are still recorded alongside the log records.
+	logEvents := 0
+
+	for _, event := range spans[0].Events {
+		if event.Name == "log" {
+			logEvents++
+		}
+	}
+
+	if logEvents != 2 {
+		t.Errorf("expected 2 log span events, got %d", logEvents)
+	}
+}
+
+func TestT_Assertions(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	tests := []struct {
+		name       string
+		assert     func(st *spectra.T)
+		wantPassed bool
+		wantAttrs  map[attribute.Key]string
+	}{
+		{
+			name:       "equal_passes",
+			assert:     func(st *spectra.T) { st.AssertEqual("items", []int{1, 2}, []int{1, 2}) },
+			wantPassed: true,
+			wantAttrs:  map[attribute.Key]string{"assertion.name": "items", "assertion.got": "[1 2]", "assertion.want": "[1 2]"},
+		},
+		{
+			name:       "equal_fails",
+			assert:     func(st *spectra.T) { st.AssertEqual("count", 1, 2) },
+			wantPassed: false,
+			wantAttrs:  map[attribute.Key]string{"assertion.name": "count", "assertion.got": "1", "assertion.want": "2"},
+		},
+		{
+			name:       "no_error_passes",
+			assert:     func(st *spectra.T) { st.AssertNoError(nil) },
+			wantPassed: true,
+			wantAttrs:  map[attribute.Key]string{"assertion.name": "no error"},
+		},
+		{
+			name:       "no_error_fails",
+			assert:     func(st *spectra.T) { st.AssertNoError(errors.New("boom")) },
+			wantPassed: false,
+			wantAttrs:  map[attribute.Key]string{"assertion.name": "no error", "assertion.error": "boom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			exporter, sp := setupTestTracer(t)
+			mock := newMockTB(tt.name)
+
+			st, err := sp.New(mock)
+			if err != nil {
+				t.Fatalf("failed to create test: %v", err)
+			}
+
+			// when
+			tt.assert(st)
+			mock.runCleanups()
+
+			// then
+			if mock.failed == tt.wantPassed {
+				t.Errorf("expected failed=%v, got %v", !tt.wantPassed, mock.failed)
+			}
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+
+			attrs := eventAttributes(spans[0], "assertion")
+			if attrs == nil {
+				t.Fatal("expected assertion event")
+			}
+
+			if got := attrs["assertion.passed"].AsBool(); got != tt.wantPassed {
+				t.Errorf("expected assertion.passed=%v, got %v", tt.wantPassed, got)
+			}
+
+			for key, want := range tt.wantAttrs {
+				if got := attrs[key].AsString(); got != want {
+					t.Errorf("expected %s=%q, got %q", key, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestT_FailedAndSkipped(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	tests := []struct {
+		name        string
+		act         func(st *spectra.T)
+		wantFailed  bool
+		wantSkipped bool
+	}{
+		{name: "passed", act: func(*spectra.T) {}},
+		{name: "error", act: func(st *spectra.T) { st.Error("boom") }, wantFailed: true},
+		{name: "skip", act: func(st *spectra.T) { st.Skip("not today") }, wantSkipped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			_, sp := setupTestTracer(t)
+			mock := newMockTB(tt.name)
+
+			st, err := sp.New(mock)
+			if err != nil {
+				t.Fatalf("failed to create test: %v", err)
+			}
+
+			t.Cleanup(mock.runCleanups)
+
+			// when
+			tt.act(st)
+
+			// then
+			if got := st.Failed(); got != tt.wantFailed {
+				t.Errorf("expected Failed()=%v, got %v", tt.wantFailed, got)
+			}
+
+			if got := st.Skipped(); got != tt.wantSkipped {
+				t.Errorf("expected Skipped()=%v, got %v", tt.wantSkipped, got)
+			}
+		})
+	}
+}
+
+func TestT_Fatal(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_Fatal")
+
+	// when
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	st.Fatal("fatal error")
+	mock.runCleanups()
+
+	// then
+	spans := exporter.GetSpans()
+
+	var targetSpan tracetest.SpanStub
+
+	for _, s := range spans {
+		if s.Name == "TestT_Fatal" {
+			targetSpan = s
+
+			break
+		}
+	}
+
+	fatalFound := false
+
+	for _, event := range targetSpan.Events {
+		if event.Name == "log" {
+			for _, attr := range event.Attributes {
+				if attr.Key == "level" && attr.Value.AsString() == "fatal" {
+					fatalFound = true
+				}
+			}
+		}
+	}
+
+	if !fatalFound {
+		t.Error("expected fatal log event not found")
+	}
+
+	if targetSpan.Status.Code != codes.Error {
+		t.Error("expected span status to be Error")
+	}
+}
+
+func TestT_Fatalf(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_Fatalf")
+
+	// when
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	st.Fatalf("fatal error: %s", "formatted")
+	mock.runCleanups()
+
+	// then
+	spans := exporter.GetSpans()
+
+	var targetSpan tracetest.SpanStub
+
+	for _, s := range spans {
+		if s.Name == "TestT_Fatalf" {
+			targetSpan = s
+
+			break
+		}
+	}
+
+	fatalFound := false
+
+	for _, event := range targetSpan.Events {
+		if event.Name == "log" {
+			for _, attr := range event.Attributes {
+				if attr.Key == "level" && attr.Value.AsString() == "fatal" {
+					fatalFound = true
+				}
+			}
+		}
+	}
+
+	if !fatalFound {
+		t.Error("expected fatal log event not found")
+	}
+}
+
+func TestT_RecoverPanic(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_RecoverPanic")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when - the test body panics under a deferred RecoverPanic.
+	var repanicked any
+
+	func() {
+		defer func() { repanicked = recover() }()
+		defer st.RecoverPanic()
+
+		panic("boom")
+	}()
+
+	mock.runCleanups()
+
+	// then - the panic is re-raised unchanged.
+	if repanicked != "boom" {
+		t.Errorf("expected panic value 'boom' to be re-raised, got %v", repanicked)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("expected span status Error, got %v", spans[0].Status.Code)
+	}
+
+	stackFound := false
+
+	for _, event := range spans[0].Events {
+		if event.Name != "exception" {
+			continue
+		}
+
+		for _, attr := range event.Attributes {
+			if attr.Key == "exception.stacktrace" && attr.Value.AsString() != "" {
+				stackFound = true
+			}
+		}
+	}
+
+	if !stackFound {
+		t.Error("expected exception event with stacktrace")
+	}
+}
+
+func TestT_Skip(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_Skip")
+
+	// when
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	st.Skip("skipping test")
+	mock.runCleanups()
+
+	// then
+	spans := exporter.GetSpans()
+
+	var targetSpan tracetest.SpanStub
+
+	for _, s := range spans {
+		if s.Name == "TestT_Skip" {
+			targetSpan = s
+
+			break
+		}
+	}
+
+	skipFound := false
+
+	for _, event := range targetSpan.Events {
+		if event.Name == "log" {
+			for _, attr := range event.Attributes {
+				if attr.Key == "level" && attr.Value.AsString() == "skip" {
+					skipFound = true
+				}
+			}
+		}
+	}
+
+	if !skipFound {
+		t.Error("expected skip log event not found")
+	}
+
+	if !mock.skipped {
+		t.Error("expected mock to be marked as skipped")
+	}
+}
+
+func TestT_Skipf(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_Skipf")
+
+	// when
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	st.Skipf("skipping: %s", "reason")
+	mock.runCleanups()
+
+	// then
+	spans := exporter.GetSpans()
+
+	var targetSpan tracetest.SpanStub
+
+	for _, s := range spans {
+		if s.Name == "TestT_Skipf" {
+			targetSpan = s
+
+			break
+		}
+	}
+
+	skipFound := false
+
+	for _, event := range targetSpan.Events {
+		if event.Name == "log" {
+			for _, attr := range event.Attributes {
+				if attr.Key == "level" && attr.Value.AsString() == "skip" {
+					skipFound = true
+				}
+			}
+		}
+	}
+
+	if !skipFound {
+		t.Error("expected skip log event not found")
+	}
+}
+
+func TestT_Parallel(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	_, sp := setupTestTracer(t)
+
+	// when - run in subtest with Parallel.
+	t.Run("parallel_test", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Parallel()
+		st.Log("running in parallel")
+	})
+
+	// then - test passes if no panic occurred.
+}
+
+func TestT_Parallel_LinksToParent(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+
+	// when
+	t.Run("parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Run("parallel_subtest", func(subST *spectra.T) {
+			subST.Parallel()
+			subST.Log("running in parallel")
+		})
+	})
+
+	// then
+	var parent, child []tracetest.SpanStub
+
+	for _, s := range exporter.GetSpans() {
+		switch s.Name {
+		case "TestT_Parallel_LinksToParent/parent":
+			parent = append(parent, s)
+		case "TestT_Parallel_LinksToParent/parent/parallel_subtest":
+			child = append(child, s)
+		}
+	}
+
+	if len(parent) != 1 || len(child) != 1 {
+		t.Fatalf("expected 1 parent and 1 child span, got %d and %d", len(parent), len(child))
+	}
+
+	if child[0].Parent.IsValid() {
+		t.Error("expected parallel subtest span to be a root span")
+	}
+
+	if len(child[0].Links) != 1 {
+		t.Fatalf("expected 1 link, got %d", len(child[0].Links))
+	}
+
+	if !child[0].Links[0].SpanContext.Equal(parent[0].SpanContext) {
+		t.Error("expected link to the parent span context")
+	}
+}
+
+func TestT_RunParallel(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	names := []string{"first", "second", "third"}
+
+	// when
+	t.Run("parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		for _, name := range names {
+			st.RunParallel(name, func(subST *spectra.T) {
+				subST.SetAttributes(attribute.String("case", name))
+			})
+		}
+	})
+
+	// then
+	var parent *tracetest.SpanStub
+
+	children := make(map[string]tracetest.SpanStub)
+
+	for _, s := range exporter.GetSpans() {
+		if s.Name == "TestT_RunParallel/parent" {
+			parent = &s
+
+			continue
+		}
+
+		for _, attr := range s.Attributes {
+			if attr.Key == "case" {
+				children[attr.Value.AsString()] = s
+			}
+		}
+	}
+
+	if parent == nil {
+		t.Fatal("expected parent span")
+	}
+
+	for _, name := range names {
+		child, ok := children[name]
+		if !ok {
+			t.Errorf("expected span for subtest %q", name)
+
+			continue
+		}
+
+		if child.Name != "TestT_RunParallel/parent/"+name {
+			t.Errorf("expected subtest %q to record its own name, got span %q", name, child.Name)
+		}
+
+		if child.Parent.IsValid() {
+			t.Errorf("expected subtest %q span to be a root span", name)
+		}
+
+		if len(child.Links) != 1 || !child.Links[0].SpanContext.Equal(parent.SpanContext) {
+			t.Errorf("expected subtest %q span to link to the parent, got %v", name, child.Links)
+		}
+	}
+}
+
+func TestRunTable(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	cases := map[string]int{
+		"charlie": 3,
+		"alpha":   1,
+		"bravo":   2,
+	}
+
+	var got []int
+
+	// when
+	t.Run("parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		ok := spectra.RunTable(st, cases, func(_ *spectra.T, tc int) {
+			got = append(got, tc)
+		})
+		if !ok {
+			innerT.Error("expected every case to pass")
+		}
+	})
+
+	// then
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("expected cases in sorted key order, got %v", got)
+	}
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+
+	for i, name := range []string{"alpha", "bravo", "charlie"} {
+		span, ok := spans["TestRunTable/parent/"+name]
+		if !ok {
+			t.Errorf("expected span for case %q", name)
+
+			continue
+		}
+
+		attrs := attribute.NewSet(span.Attributes...)
+
+		if v, _ := attrs.Value("test.case"); v.AsString() != name {
+			t.Errorf("expected test.case %q, got %q", name, v.AsString())
+		}
+
+		if v, _ := attrs.Value("test.case_index"); v.AsInt64() != int64(i) {
+			t.Errorf("expected test.case_index %d for case %q, got %d", i, name, v.AsInt64())
+		}
+	}
+}
+
+func TestRunTable_Parallel(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	cases := map[string]int{
+		"alpha": 1,
+		"bravo": 2,
+	}
+
+	// when
+	t.Run("parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		spectra.RunTable(st, cases, func(st *spectra.T, _ int) {
+			st.Parallel()
+		})
+	})
+
+	// then
+	spans := make(map[string]tracetest.SpanStub)
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+
+	for i, name := range []string{"alpha", "bravo"} {
+		span, ok := spans["TestRunTable_Parallel/parent/"+name]
+		if !ok {
+			t.Errorf("expected span for case %q", name)
+
+			continue
+		}
+
+		if span.Parent.IsValid() {
+			t.Errorf("expected case %q span to be a root span", name)
+		}
+
+		attrs := attribute.NewSet(span.Attributes...)
+
+		if v, _ := attrs.Value("test.case"); v.AsString() != name {
+			t.Errorf("expected test.case %q, got %q", name, v.AsString())
+		}
+
+		if v, _ := attrs.Value("test.case_index"); v.AsInt64() != int64(i) {
+			t.Errorf("expected test.case_index %d for case %q, got %d", i, name, v.AsInt64())
+		}
+	}
+}
+
+func TestT_Parallel_EndsDetachedSpan(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var order []string
+
+	processor := &countingProcessor{name: "counting", order: &order}
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(exporter),
+		spectra.WithSpanProcessor(processor),
+		spectra.WithoutMetrics(),
+		spectra.WithGlobalProviders(false),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// when
+	t.Run("parent", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.RunParallel("child", func(_ *spectra.T) {})
+	})
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	if processor.started != len(order) {
+		t.Errorf("expected every started span to end, got %d starts and %d ends", processor.started, len(order))
+	}
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+
+	parent := spans["TestT_Parallel_EndsDetachedSpan/parent"]
+
+	detached, ok := spans["TestT_Parallel_EndsDetachedSpan/parent/child/parallel"]
+	if !ok {
+		t.Fatal("expected the detached span to be ended as the parallel span")
+	}
+
+	if detached.Parent.SpanID() != parent.SpanContext.SpanID() {
+		t.Error("expected the parallel span to be a child of the parent test span")
+	}
+
+	attrs := attribute.NewSet(detached.Attributes...)
+	if phase, _ := attrs.Value("test.phase"); phase.AsString() != "parallel" {
+		t.Errorf("expected test.phase %q on the parallel span, got %q", "parallel", phase.AsString())
+	}
+
+	if _, ok := spans["TestT_Parallel_EndsDetachedSpan/parent/child"]; !ok {
+		t.Error("expected the subtest span")
+	}
+}
+
+func TestSpectra_NewB(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+
+	// when
+	result := testing.Benchmark(func(b *testing.B) {
+		sb, err := sp.NewB(b)
+		if err != nil {
+			b.Fatalf("failed to create benchmark: %v", err)
+		}
+
+		_, span := sb.StartSpan("operation")
+		span.End()
+
+		for b.Loop() {
+			_ = sb.Context()
+		}
+
+		sb.ReportMetric(1, "widgets/op")
+	})
+
+	// then - one benchmark span per invocation, each with b.N and timings.
+	var benchSpans []tracetest.SpanStub
+
+	for _, s := range exporter.GetSpans() {
+		if s.Name != "operation" {
+			benchSpans = append(benchSpans, s)
+		}
+	}
+
+	if len(benchSpans) == 0 {
+		t.Fatal("expected at least one benchmark span")
+	}
+
+	last := benchSpans[len(benchSpans)-1]
+	attrs := make(map[attribute.Key]attribute.Value)
+
+	for _, attr := range last.Attributes {
+		attrs[attr.Key] = attr.Value
+	}
+
+	if got := attrs["benchmark.n"].AsInt64(); got != int64(result.N) {
+		t.Errorf("expected benchmark.n %d, got %d", result.N, got)
+	}
+
+	for _, key := range []attribute.Key{"benchmark.ns_per_op", "benchmark.allocs_per_op", "benchmark.metric.widgets/op"} {
+		if _, ok := attrs[key]; !ok {
+			t.Errorf("expected attribute %q on benchmark span", key)
+		}
+	}
+}
+
+func TestB_Run(t *testing.T) {
+	t.Parallel()
+
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.NewWithExporter(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithDefaultAttributes(attribute.String("team", "payments")),
+		spectra.WithSpanNamer(func(name string) string { return "bench:" + name }),
+	), exporter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	suiteCtx := sp.BeginSuite("benchmarks")
+	suite := trace.SpanContextFromContext(suiteCtx)
+
+	// when
+	testing.Benchmark(func(b *testing.B) {
+		sb, err := sp.NewB(b)
+		if err != nil {
+			b.Fatalf("failed to create benchmark: %v", err)
+		}
+
+		sb.Run("sub", func(sub *spectra.B) {
+			for sub.Loop() {
+				_ = sub.Context()
+			}
+		})
+	})
+
+	sp.EndSuite()
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then - testing.Benchmark runs unnamed benchmarks, so the span namer
+	// output is all that names the spans.
+	spans := exporter.GetSpans()
+	parentIDs := make(map[trace.SpanID]bool)
+
+	for _, s := range spans {
+		if s.Parent.SpanID() == suite.SpanID() && s.Name == "bench:" {
+			parentIDs[s.SpanContext.SpanID()] = true
+		}
+	}
+
+	if len(parentIDs) != 1 {
+		t.Fatalf("expected 1 benchmark span under the suite span, got %d", len(parentIDs))
+	}
+
+	subs := 0
+
+	for _, s := range spans {
+		if s.Name == "benchmarks" {
+			continue
+		}
+
+		attrs := attribute.NewSet(s.Attributes...)
+		if team, _ := attrs.Value("team"); team.AsString() != "payments" {
+			t.Errorf("expected default attribute on %q, got %q", s.Name, team.AsString())
+		}
+
+		if parentIDs[s.Parent.SpanID()] {
+			subs++
+		}
+	}
+
+	if subs == 0 {
+		t.Error("expected sub-benchmark spans as children of the benchmark span")
+	}
+}
+
+func FuzzSpectra_NewF(f *testing.F) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(f)
+
+	sf, err := sp.NewF(f)
+	if err != nil {
+		f.Fatalf("failed to create fuzz test: %v", err)
+	}
+
+	sf.Add([]byte("seed"))
+	sf.Add([]byte("longer seed"))
+
+	// when
+	sf.Fuzz(func(st *spectra.T, data []byte) {
+		st.Logf("fuzzing %d bytes", len(data))
+	})
+
+	// then - each seed input ran in its own span recording the input size.
+	// When actively fuzzing, inputs run in worker processes instead.
+	if fuzzFlag := flag.Lookup("test.fuzz"); fuzzFlag != nil && fuzzFlag.Value.String() != "" {
+		return
+	}
+
+	sizes := make(map[int64]bool)
+
+	for _, s := range exporter.GetSpans() {
+		for _, attr := range s.Attributes {
+			if attr.Key == "fuzz.input.bytes" {
+				sizes[attr.Value.AsInt64()] = true
+			}
+		}
+	}
+
+	for _, want := range []int64{4, 11} {
+		if !sizes[want] {
+			f.Errorf("expected fuzz span with input size %d, got %v", want, sizes)
+		}
+	}
+}
+
+func TestInit(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	endpoint, _ := startGRPCCollector(t)
+
+	// when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint(endpoint),
+		spectra.WithInsecure(),
+	)
+	// then - should return a valid Spectra instance.
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sp == nil {
+		t.Error("expected non-nil Spectra instance")
+	}
+
+	// Cleanup.
+	err = sp.Shutdown()
+	if err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestInit_HTTP(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	collector := startHTTPCollector(t)
+
+	// when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint(collector.URL),
+	)
+	// then - should return a valid Spectra instance.
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sp == nil {
+		t.Error("expected non-nil Spectra instance")
+	}
+
+	err = sp.Shutdown()
+	if err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestInit_HTTPS(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	collector := httptest.NewTLSServer(http.HandlerFunc(acceptExport))
+	defer collector.Close()
+
+	transport, ok := collector.Client().Transport.(*http.Transport)
+	if !ok {
+		t.Fatal("expected an *http.Transport")
+	}
+
+	// when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint(collector.URL),
+		spectra.WithTLSConfig(transport.TLSClientConfig),
+	)
+	// then - should return a valid Spectra instance.
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sp == nil {
+		t.Error("expected non-nil Spectra instance")
+	}
+
+	err = sp.Shutdown()
+	if err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestInit_HTTPS_Insecure(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given - insecure skips verifying the collector's self-signed certificate.
+	collector := httptest.NewTLSServer(http.HandlerFunc(acceptExport))
+	defer collector.Close()
+
+	// when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint(collector.URL),
+		spectra.WithInsecure(),
+	)
+	// then - should return a valid Spectra instance.
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sp == nil {
+		t.Error("expected non-nil Spectra instance")
+	}
+
+	err = sp.Shutdown()
+	if err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestInit_InvalidEndpoint(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given/when - endpoint without scheme
+	_, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("localhost:4317"),
+	)
+
+	// then - should return error
+	if err == nil {
+		t.Fatal("expected error for endpoint without scheme")
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{name: "grpc_host_port", endpoint: "grpc://collector:4317", want: "collector:4317"},
+		{name: "grpc_default_port", endpoint: "grpc://collector", want: "collector:4317"},
+		{name: "http_default_port", endpoint: "http://collector", want: "collector:4318"},
+		{name: "https_explicit_port", endpoint: "https://collector:443", want: "collector:443"},
+		{name: "ipv6_default_port", endpoint: "grpc://[::1]", want: "[::1]:4317"},
+		{name: "stdout", endpoint: "stdout://", want: ""},
+		{name: "unix_socket", endpoint: "unix:///var/run/otel.sock", want: "/var/run/otel.sock"},
+		{name: "unix_empty", endpoint: "unix://", wantErr: true},
+		{name: "grpc_empty", endpoint: "grpc://", wantErr: true},
+		{name: "grpc_colon_only", endpoint: "grpc://:", wantErr: true},
+		{name: "missing_host", endpoint: "grpc://:4317", wantErr: true},
+		{name: "non_numeric_port", endpoint: "grpc://collector:otlp", wantErr: true},
+		{name: "port_out_of_range", endpoint: "grpc://collector:70000", wantErr: true},
+		{name: "missing_scheme", endpoint: "collector:4317", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			got, err := spectra.ParseEndpoint(tt.endpoint)
+
+			// then
+			if tt.wantErr {
+				if !errors.Is(err, spectra.ErrInvalidEndpoint) {
+					t.Errorf("expected ErrInvalidEndpoint, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInit_EndpointFromEnv(t *testing.T) {
+	// Tests modify environment variables - cannot run in parallel.
+
+	// given
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "grpc://localhost:4317")
+
+	// when - no WithEndpoint option
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithoutMetrics(),
+	)
+	// then - should pick up the endpoint from the environment.
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = sp.Shutdown()
+	if err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestInit_EndpointOptionOverridesEnv(t *testing.T) {
+	// Tests modify environment variables - cannot run in parallel.
+
+	// given - env var without a valid scheme
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4317")
+
+	// when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithoutMetrics(),
+	)
+	// then - explicit option wins, so the invalid env var is ignored.
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = sp.Shutdown()
+	if err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestInit_EndpointFromEnvInvalid(t *testing.T) {
+	// Tests modify environment variables - cannot run in parallel.
+
+	// given
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4317")
+
+	// when
+	_, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+	)
+
+	// then
+	if !errors.Is(err, spectra.ErrInvalidEndpoint) {
+		t.Errorf("expected ErrInvalidEndpoint, got %v", err)
+	}
+}
+
+func TestInit_MissingEndpoint(t *testing.T) {
+	// Tests modify environment variables - cannot run in parallel.
+
+	// given
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	// when
+	_, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+	)
+
+	// then
+	if !errors.Is(err, spectra.ErrMissingEndpoint) {
+		t.Errorf("expected ErrMissingEndpoint, got %v", err)
+	}
+}
+
+func TestInit_ServiceNameFromEnv(t *testing.T) {
+	// Tests modify environment variables - cannot run in parallel.
+
+	// given
+	t.Setenv("OTEL_SERVICE_NAME", "env-service")
+
+	// when - no WithServiceName option
+	sp, err := spectra.Init(
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithoutMetrics(),
+	)
+	// then - should pick up the service name from the environment.
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	cfg := spectra.ResolvedConfig(sp)
+	if cfg.ServiceName != "env-service" {
+		t.Errorf("expected service name 'env-service', got %q", cfg.ServiceName)
+	}
+}
+
+func TestInit_ServiceNameOptionOverridesEnv(t *testing.T) {
+	// Tests modify environment variables - cannot run in parallel.
+
+	// given
+	t.Setenv("OTEL_SERVICE_NAME", "env-service")
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("option-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// when
+	cfg := spectra.ResolvedConfig(sp)
+
+	// then - explicit option wins over the environment.
+	if cfg.ServiceName != "option-service" {
+		t.Errorf("expected service name 'option-service', got %q", cfg.ServiceName)
+	}
+}
+
+func TestInit_MissingServiceName(t *testing.T) {
+	// Tests modify environment variables - cannot run in parallel.
+
+	// given
+	t.Setenv("OTEL_SERVICE_NAME", "")
+
+	// when
+	_, err := spectra.Init(
+		spectra.WithEndpoint("grpc://localhost:4317"),
+	)
+
+	// then
+	if !errors.Is(err, spectra.ErrMissingServiceName) {
+		t.Errorf("expected ErrMissingServiceName, got %v", err)
+	}
+}
+
+func TestWithHeaders(t *testing.T) {
+	t.Parallel()
+
+	// given
+	headers := map[string]string{
+		"Authorization": "Bearer token",
+		"X-Tenant":      "tests",
+	}
+
+	// when
+	cfg := spectra.NewConfig(spectra.WithHeaders(headers))
+
+	// then
+	if len(cfg.Headers) != len(headers) {
+		t.Fatalf("expected %d headers, got %d", len(headers), len(cfg.Headers))
+	}
+
+	for key, want := range headers {
+		if got := cfg.Headers[key]; got != want {
+			t.Errorf("expected header %q to be %q, got %q", key, want, got)
+		}
+	}
+}
+
+func TestInit_WithHeaders(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given/when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("http://localhost:4318"),
+		spectra.WithHeaders(map[string]string{"Authorization": "Bearer token"}),
+		spectra.WithoutMetrics(),
+	)
+	// then
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = sp.Shutdown()
+	if err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestInit_WithCompression(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	for _, endpoint := range []string{"grpc://localhost:4317", "http://localhost:4318"} {
+		t.Run(endpoint, func(t *testing.T) {
+			// given/when
+			sp, err := spectra.Init(
+				spectra.WithServiceName("test-service"),
+				spectra.WithEndpoint(endpoint),
+				spectra.WithCompression(spectra.CompressionGzip),
+				spectra.WithoutMetrics(),
+			)
+			// then
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if cfg := spectra.ResolvedConfig(sp); cfg.Compression != spectra.CompressionGzip {
+				t.Errorf("expected compression %q, got %q", spectra.CompressionGzip, cfg.Compression)
+			}
+
+			err = sp.Shutdown()
+			if err != nil {
+				t.Errorf("unexpected shutdown error: %v", err)
+			}
+		})
+	}
+}
+
+func TestInit_WithCompression_HTTPContentEncoding(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	var (
+		mu        sync.Mutex
+		encodings []string
+	)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		mu.Unlock()
+
+		acceptExport(w, r)
+	}))
+	defer collector.Close()
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint(collector.URL),
+		spectra.WithCompression(spectra.CompressionGzip),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("TestInit_WithCompression_HTTPContentEncoding")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	mock.runCleanups()
+
+	// when
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(encodings) == 0 {
+		t.Fatal("expected the collector to receive an export")
+	}
+
+	for _, encoding := range encodings {
+		if encoding != "gzip" {
+			t.Errorf("expected Content-Encoding gzip, got %q", encoding)
+		}
+	}
+}
+
+func TestInit_InvalidCompression(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given/when
+	_, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithCompression("zstd"),
+	)
+
+	// then
+	if !errors.Is(err, spectra.ErrInvalidCompression) {
+		t.Errorf("expected ErrInvalidCompression, got %v", err)
+	}
+}
+
+func TestWithExportTimeout(t *testing.T) {
+	t.Parallel()
+
+	// given/when
+	cfg := spectra.NewConfig(
+		spectra.WithExportTimeout(2*time.Second),
+		spectra.WithShutdownTimeout(time.Second),
+	)
+
+	// then - export timeout is independent of shutdown timeout.
+	if cfg.ExportTimeout != 2*time.Second {
+		t.Errorf("expected export timeout 2s, got %v", cfg.ExportTimeout)
+	}
+
+	if cfg.ShutdownTimeout != time.Second {
+		t.Errorf("expected shutdown timeout 1s, got %v", cfg.ShutdownTimeout)
+	}
+}
+
+func TestInit_DefaultExportTimeout(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given/when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// then
+	if got := spectra.ResolvedConfig(sp).ExportTimeout; got != 10*time.Second {
+		t.Errorf("expected default export timeout 10s, got %v", got)
+	}
+}
+
+func TestWithRetryConfig(t *testing.T) {
+	t.Parallel()
+
+	// given/when
+	cfg := spectra.NewConfig(spectra.WithRetryConfig(time.Second, 10*time.Second, time.Minute))
+	defaults := spectra.NewConfig()
+
+	// then
+	if cfg.Retry == nil {
+		t.Fatal("expected retry config to be set")
+	}
+
+	if cfg.Retry.InitialInterval != time.Second {
+		t.Errorf("expected initial interval 1s, got %v", cfg.Retry.InitialInterval)
+	}
+
+	if cfg.Retry.MaxInterval != 10*time.Second {
+		t.Errorf("expected max interval 10s, got %v", cfg.Retry.MaxInterval)
+	}
+
+	if cfg.Retry.MaxElapsedTime != time.Minute {
+		t.Errorf("expected max elapsed time 1m, got %v", cfg.Retry.MaxElapsedTime)
+	}
+
+	if defaults.Retry != nil {
+		t.Errorf("expected exporter defaults without the option, got %+v", defaults.Retry)
+	}
+}
+
+func TestWithSamplingRatio(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		ratio float64
+		want  int
+	}{
+		{name: "ratio_zero_exports_nothing", ratio: 0, want: 0},
+		{name: "ratio_one_exports_everything", ratio: 1, want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			exporter := tracetest.NewInMemoryExporter()
+
+			tp, err := spectra.NewTracerProvider(spectra.NewConfig(
+				spectra.WithServiceName("test-service"),
+				spectra.WithEndpoint("grpc://localhost:4317"),
+				spectra.WithSamplingRatio(tt.ratio),
+			), exporter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// when
+			tracer := tp.Tracer("test")
+			for range 10 {
+				_, span := tracer.Start(context.Background(), "span")
+				span.End()
+			}
+
+			err = tp.ForceFlush(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected flush error: %v", err)
+			}
+
+			t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+			// then
+			if got := len(exporter.GetSpans()); got != tt.want {
+				t.Errorf("expected %d exported spans, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWithSpanProcessor(t *testing.T) {
+	// given
+	var order []string
+
+	first := &countingProcessor{name: "first", order: &order}
+	second := &countingProcessor{name: "second", order: &order}
+
+	tp, err := spectra.NewTracerProvider(spectra.NewConfig(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithSpanProcessor(first),
+		spectra.WithSpanProcessor(second),
+	), tracetest.NewInMemoryExporter())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	// when
+	tracer := tp.Tracer("test")
+	for range 3 {
+		_, span := tracer.Start(context.Background(), "span")
+		span.End()
+	}
+
+	// then
+	if first.started != 3 || second.started != 3 {
+		t.Errorf("expected both processors to see 3 span starts, got %d and %d", first.started, second.started)
+	}
+
+	if len(order) < 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("expected processors to run in registration order, got %v", order)
+	}
+}
+
+func TestWithSpanLimits(t *testing.T) {
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+	limits := sdktrace.NewSpanLimits()
+	limits.AttributeCountLimit = 2
+
+	tp, err := spectra.NewTracerProvider(spectra.NewConfig(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithSpanLimits(limits),
+	), exporter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	// when
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	for i := range 5 {
+		span.SetAttributes(attribute.Int(fmt.Sprintf("attr.%d", i), i))
+	}
+
+	span.End()
+
+	err = tp.ForceFlush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if got := len(spans[0].Attributes); got != 2 {
+		t.Errorf("expected 2 attributes kept, got %d", got)
+	}
+
+	if got := spans[0].DroppedAttributes; got != 3 {
+		t.Errorf("expected 3 dropped attributes, got %d", got)
+	}
+}
+
+func TestInit_SignalEndpoints(t *testing.T) {
+	// Tests modify environment variables - cannot run in parallel.
+
+	// given
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	tracesEndpoint, _ := startGRPCCollector(t)
+	metricsEndpoint := startHTTPCollector(t).URL
+
+	// when - no shared endpoint, one per signal
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithTracesEndpoint(tracesEndpoint),
+		spectra.WithMetricsEndpoint(metricsEndpoint),
+		spectra.WithInsecure(),
+	)
+	// then
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	cfg := spectra.ResolvedConfig(sp)
+	if cfg.TracesEndpoint != tracesEndpoint {
+		t.Errorf("expected traces endpoint %q, got %q", tracesEndpoint, cfg.TracesEndpoint)
+	}
+
+	if cfg.MetricsEndpoint != metricsEndpoint {
+		t.Errorf("expected metrics endpoint %q, got %q", metricsEndpoint, cfg.MetricsEndpoint)
+	}
+}
+
+func TestInit_SignalEndpointOverridesShared(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given/when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithTracesEndpoint("http://localhost:4318"),
+		spectra.WithoutMetrics(),
+	)
+	// then
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	if got := spectra.ResolvedConfig(sp).TracesEndpoint; got != "http://localhost:4318" {
+		t.Errorf("expected traces endpoint 'http://localhost:4318', got %q", got)
+	}
+}
+
+func TestInit_SignalEndpointMissing(t *testing.T) {
+	// Tests modify environment variables - cannot run in parallel.
+
+	// given
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	// when - metrics enabled but only traces has an endpoint
+	_, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithTracesEndpoint("grpc://localhost:4317"),
+	)
+
+	// then
+	if !errors.Is(err, spectra.ErrMissingEndpoint) {
+		t.Errorf("expected ErrMissingEndpoint, got %v", err)
+	}
+}
+
+func TestInit_SignalEndpointInvalid(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given/when
+	_, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithMetricsEndpoint("localhost:4318"),
+	)
+
+	// then
+	if !errors.Is(err, spectra.ErrInvalidEndpoint) {
+		t.Errorf("expected ErrInvalidEndpoint, got %v", err)
+	}
+}
+
+func TestInit_StdoutExporter(t *testing.T) {
+	// Tests modify environment variables - cannot run in parallel.
+
+	// given - no network endpoint anywhere, and stderr redirected while the
+	// exporters pick up their writer.
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+
+	defer func() { _ = reader.Close() }()
+
+	output := make(chan string, 1)
+
+	go func() {
+		data, _ := io.ReadAll(reader)
+		output <- string(data)
+	}()
+
+	stderr := os.Stderr
+	os.Stderr = writer
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithStdoutExporter(),
+	)
+
+	os.Stderr = stderr
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mock := newMockTB("TestInit_StdoutExporter")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	mock.runCleanups()
+
+	// when
+	err = sp.Shutdown()
+	if err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("failed to close pipe: %v", err)
+	}
+
+	// then
+	if got := <-output; !strings.Contains(got, `"Name": "TestInit_StdoutExporter"`) {
+		t.Errorf("expected the test span on stderr, got:\n%s", got)
+	}
+}
+
+func TestInit_UnixSocket(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	socket, collector := startUnixCollector(t)
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("unix://"+socket),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("over-unix-socket")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	mock.runCleanups()
+
+	err = sp.Flush(context.Background())
+
+	// then
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	if got := collector.Names(); !slices.Equal(got, []string{"over-unix-socket"}) {
+		t.Errorf("expected collector to receive 'over-unix-socket', got %v", got)
+	}
+}
+
+func TestInit_WithMetricReader(t *testing.T) {
+	// Tests modify environment variables and global meter provider - cannot run in parallel.
+
+	// given - no endpoint anywhere
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	reader := sdkmetric.NewManualReader()
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithMetricReader(reader),
+		spectra.WithoutTraces(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("collected")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	mock.runCleanups()
+
+	// then
+	if dp := durationHistogram(t, reader, "collected"); dp.Count != 1 {
+		t.Errorf("expected 1 recorded duration, got %d", dp.Count)
+	}
+}
+
+func TestInit_WithMetricReaderAndEndpoint(t *testing.T) {
+	t.Parallel()
+
+	// given/when
+	_, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithMetricsEndpoint("grpc://localhost:4317"),
+		spectra.WithMetricReader(sdkmetric.NewManualReader()),
+	)
+
+	// then
+	if !errors.Is(err, spectra.ErrMetricReaderWithEndpoint) {
+		t.Errorf("expected ErrMetricReaderWithEndpoint, got %v", err)
+	}
+}
+
+func TestInit_WithPrometheusExporter(t *testing.T) {
+	// Tests modify environment variables and global meter provider - cannot run in parallel.
+
+	// given - no endpoint anywhere
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithPrometheusExporter(),
+		spectra.WithoutTraces(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("scraped")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	mock.runCleanups()
+
+	// when
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/metrics", nil)
+	sp.MetricsHandler().ServeHTTP(recorder, request)
+
+	// then
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+
+	if body := recorder.Body.String(); !strings.Contains(body, "test_count") {
+		t.Errorf("expected scrape to contain test_count, got:\n%s", body)
+	}
+}
+
+func TestSpectra_MetricsHandlerWithoutPrometheus(t *testing.T) {
+	t.Parallel()
+
+	// given
+	sp, err := spectra.Init(spectra.WithDisabled())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// when
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/metrics", nil)
+	sp.MetricsHandler().ServeHTTP(recorder, request)
+
+	// then
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", recorder.Code)
+	}
+}
+
+func TestInit_WithSpanExporter(t *testing.T) {
+	// Tests modify environment variables and global tracer provider - cannot run in parallel.
+
+	// given - no endpoint anywhere
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(exporter),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("in-memory")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	mock.runCleanups()
+
+	err = sp.Flush(context.Background())
+
+	// then
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "in-memory" {
+		t.Errorf("expected exporter to receive 'in-memory', got %v", spans)
+	}
+}
+
+func TestInit_SpanKindAndFrameworkResource(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(exporter),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// when
+	t.Run("kinds", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Setup(func(_ context.Context) {})
+		st.Teardown(func(_ context.Context) {})
+		st.Run("subtest", func(_ *spectra.T) {})
+	})
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	spans := exporter.GetSpans()
+	if len(spans) != 4 {
+		t.Fatalf("expected test, setup, teardown and subtest spans, got %d", len(spans))
+	}
+
+	for _, span := range spans {
+		if span.SpanKind != trace.SpanKindInternal {
+			t.Errorf("expected span %q to be Internal, got %v", span.Name, span.SpanKind)
+		}
+
+		if framework, ok := span.Resource.Set().Value("test.framework"); !ok || framework.AsString() != "spectra" {
+			t.Errorf("expected span %q resource test.framework=spectra, got %q", span.Name, framework.AsString())
+		}
+	}
+}
+
+func TestInit_WithAttributesFromEnv(t *testing.T) {
+	// Tests modify environment variables and global tracer provider - cannot run in parallel.
+
+	// given
+	t.Setenv("SPECTRA_CI_JOB_ID", "1234")
+	t.Setenv("SPECTRA_CI_COMMIT_REF_NAME", "main")
+
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(exporter),
+		spectra.WithAttributesFromEnv("SPECTRA_CI_"),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// env changes after Init are not picked up.
+	t.Setenv("SPECTRA_CI_LATE", "ignored")
+
+	mock := newMockTB("tagged")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	mock.runCleanups()
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	attrs := attribute.NewSet(spans[0].Attributes...)
+
+	if got, _ := attrs.Value("job.id"); got.AsString() != "1234" {
+		t.Errorf("expected job.id 1234, got %q", got.AsString())
+	}
+
+	if got, _ := attrs.Value("commit.ref.name"); got.AsString() != "main" {
+		t.Errorf("expected commit.ref.name main, got %q", got.AsString())
+	}
+
+	if attrs.HasValue("late") {
+		t.Error("expected variables set after Init to be ignored")
+	}
+}
+
+func TestInit_WithDefaultAttributes(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(exporter),
+		spectra.WithDefaultAttributes(attribute.String("test.suite", "checkout")),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// when
+	t.Run("defaults", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Run("subtest", func(_ *spectra.T) {})
+		st.Run("overridden", func(subST *spectra.T) {
+			subST.SetAttributes(attribute.String("test.suite", "payments"))
+		})
+	})
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	want := map[string]string{
+		"TestInit_WithDefaultAttributes/defaults":            "checkout",
+		"TestInit_WithDefaultAttributes/defaults/subtest":    "checkout",
+		"TestInit_WithDefaultAttributes/defaults/overridden": "payments",
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != len(want) {
+		t.Fatalf("expected %d spans, got %d", len(want), len(spans))
+	}
+
+	for _, span := range spans {
+		attrs := attribute.NewSet(span.Attributes...)
+
+		got, _ := attrs.Value("test.suite")
+		if got.AsString() != want[span.Name] {
+			t.Errorf("expected span %q test.suite %q, got %q", span.Name, want[span.Name], got.AsString())
+		}
+	}
+}
+
+func TestInit_WithJSONSummary_Unsampled(t *testing.T) {
+	t.Parallel()
+
+	// given
+	var summary strings.Builder
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(tracetest.NewInMemoryExporter()),
+		spectra.WithSampler(sdktrace.NeverSample()),
+		spectra.WithJSONSummary(&summary),
+		spectra.WithoutMetrics(),
+		spectra.WithGlobalProviders(false),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mock := newMockTB("unsampled")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	st.Retry(2, func(*spectra.T) error { return nil })
+	mock.runCleanups()
+
+	// when
+	err = sp.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	// then
+	var entries []struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+
+	err = json.Unmarshal([]byte(summary.String()), &entries)
+	if err != nil {
+		t.Fatalf("failed to parse summary %q: %v", summary.String(), err)
+	}
+
+	if len(entries) != 1 || entries[0].Name != "unsampled" || entries[0].Status != "pass" {
+		t.Errorf("expected only the unsampled test, got %+v", entries)
+	}
+}
+
+func TestInit_WithJSONSummary(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	var summary strings.Builder
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(tracetest.NewInMemoryExporter()),
+		spectra.WithJSONSummary(&summary),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	passing := newMockTB("passing")
+	failing := newMockTB("failing")
+
+	for _, mock := range []*mockTB{passing, failing} {
+		st, err := sp.New(mock)
+		if err != nil {
+			t.Fatalf("failed to create test: %v", err)
+		}
+
+		_, span := st.StartSpan("not-a-test")
+		span.End()
+
+		if mock == failing {
+			st.Error("boom")
+		}
+
+		mock.runCleanups()
+	}
+
+	// when
+	err = sp.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	// then
+	var entries []struct {
+		Name            string  `json:"name"`
+		Status          string  `json:"status"`
+		DurationSeconds float64 `json:"duration_seconds"`
+		TraceID         string  `json:"trace_id"`
+	}
+
+	err = json.Unmarshal([]byte(summary.String()), &entries)
+	if err != nil {
+		t.Fatalf("failed to parse summary %q: %v", summary.String(), err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+
+	for i, want := range []struct{ name, status string }{{"passing", "pass"}, {"failing", "fail"}} {
+		if entries[i].Name != want.name || entries[i].Status != want.status {
+			t.Errorf("expected %s/%s, got %s/%s", want.name, want.status, entries[i].Name, entries[i].Status)
+		}
+
+		if entries[i].DurationSeconds <= 0 || entries[i].TraceID == "" {
+			t.Errorf("expected duration and trace id for %s, got %+v", want.name, entries[i])
+		}
+	}
+}
+
+func TestInit_WithSpanNamePrefix(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(exporter),
+		spectra.WithSpanNamePrefix("billing"),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// when
+	t.Run("prefixed", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Setup(func(_ context.Context) {})
+		st.Teardown(func(_ context.Context) {})
+		st.Run("subtest", func(_ *spectra.T) {})
+	})
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	names := make([]string, 0, 4)
+	for _, span := range exporter.GetSpans() {
+		names = append(names, span.Name)
+	}
+
+	slices.Sort(names)
+
+	want := []string{
+		"billing/TestInit_WithSpanNamePrefix/prefixed",
+		"billing/TestInit_WithSpanNamePrefix/prefixed/setup",
+		"billing/TestInit_WithSpanNamePrefix/prefixed/subtest",
+		"billing/TestInit_WithSpanNamePrefix/prefixed/teardown",
+	}
+	if !slices.Equal(names, want) {
+		t.Errorf("expected spans %v, got %v", want, names)
+	}
+}
+
+func TestInit_WithGoroutineLeakCheck(t *testing.T) {
+	// Goroutine counts are process-wide - cannot run in parallel.
+
+	tests := []struct {
+		name       string
+		failOnLeak bool
+	}{
+		{name: "record", failOnLeak: false},
+		{name: "fail", failOnLeak: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			exporter := tracetest.NewInMemoryExporter()
+
+			sp, err := spectra.NewWithExporter(spectra.NewConfig(
+				spectra.WithServiceName("test"),
+				spectra.WithEndpoint("grpc://localhost:4317"),
+				spectra.WithGoroutineLeakCheck(tt.failOnLeak),
+			), exporter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			defer func() {
+				err := sp.Shutdown()
+				if err != nil {
+					t.Errorf("unexpected shutdown error: %v", err)
+				}
+			}()
+
+			mock := newMockTB(tt.name)
+
+			_, err = sp.New(mock)
+			if err != nil {
+				t.Fatalf("failed to create test: %v", err)
+			}
+
+			// when - the goroutine outlives the test.
+			release := make(chan struct{})
+			exited := make(chan struct{})
+
+			defer func() {
+				close(release)
+				<-exited
+			}()
+
+			go func() {
+				defer close(exited)
+
+				<-release
+			}()
+
+			mock.runCleanups()
+
+			err = sp.Flush(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected flush error: %v", err)
+			}
+
+			// then
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+
+			attrs := eventAttributes(spans[0], "test.goroutine_leak")
+			if attrs == nil {
+				t.Fatal("expected test.goroutine_leak event not found")
+			}
+
+			if attrs["goroutines.after"].AsInt64() <= attrs["goroutines.before"].AsInt64() {
+				t.Errorf("expected more goroutines after the test, got %v", attrs)
+			}
+
+			if mock.failed != tt.failOnLeak {
+				t.Errorf("expected failed %v, got %v", tt.failOnLeak, mock.failed)
+			}
+		})
+	}
+}
+
+// allocSink keeps test allocations from being optimized away.
+var allocSink []byte
+
+func TestInit_WithMemoryProfiling(t *testing.T) {
+	t.Parallel()
+
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.NewWithExporter(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithMemoryProfiling(),
+	), exporter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("TestInit_WithMemoryProfiling")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	allocSink = make([]byte, 1<<20)
+
+	mock.runCleanups()
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	attrs := attribute.NewSet(spans[0].Attributes...)
+
+	allocated, ok := attrs.Value("test.alloc_bytes")
+	if !ok {
+		t.Fatal("expected test.alloc_bytes attribute")
+	}
+
+	if allocated.AsInt64() < 1<<20 {
+		t.Errorf("expected test.alloc_bytes of at least %d, got %d", 1<<20, allocated.AsInt64())
+	}
+}
+
+func TestInit_WithCleanupTracing(t *testing.T) {
+	t.Parallel()
+
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.NewWithExporter(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithCleanupTracing(),
+	), exporter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("TestInit_WithCleanupTracing")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	var ran []string
+
+	// when
+	st.Cleanup(func() { ran = append(ran, "first") })
+	st.Cleanup(func() { ran = append(ran, "second") })
+
+	mock.runCleanups()
+
+	// then
+	if !slices.Equal(ran, []string{"second", "first"}) {
+		t.Errorf("expected cleanups to run in reverse order, got %v", ran)
+	}
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	var events []string
+
+	for _, event := range spans[0].Events {
+		attrs := attribute.NewSet(event.Attributes...)
+		index, _ := attrs.Value("cleanup.index")
+
+		events = append(events, fmt.Sprintf("%s:%d", event.Name, index.AsInt64()))
+	}
+
+	want := []string{
+		"cleanup.registered:1",
+		"cleanup.registered:2",
+		"cleanup.run:2",
+		"cleanup.run:1",
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("expected events %v, got %v", want, events)
+	}
+}
+
+func TestInit_WithCodeLocation(t *testing.T) {
+	t.Parallel()
+
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.NewWithExporter(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithCodeLocation(),
+	), exporter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("TestInit_WithCodeLocation")
+
+	// when
+	_, file, line, _ := runtime.Caller(0)
+	_, err = sp.New(mock)
+
+	mock.runCleanups()
+
+	// then
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range spans[0].Attributes {
+		attrs[attr.Key] = attr.Value
+	}
+
+	if got := attrs["code.filepath"].AsString(); got != file {
+		t.Errorf("expected code.filepath %q, got %q", file, got)
+	}
+
+	if got := attrs["code.lineno"].AsInt64(); got != int64(line+1) {
+		t.Errorf("expected code.lineno %d, got %d", line+1, got)
+	}
+}
+
+type requestIDKey struct{}
+
+func TestInit_WithContextAttributeExtractor(t *testing.T) {
+	t.Parallel()
+
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.NewWithExporter(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithContextAttributeExtractor(func(ctx context.Context) []attribute.KeyValue {
+			id, ok := ctx.Value(requestIDKey{}).(string)
+			if !ok {
+				return nil
+			}
+
+			return []attribute.KeyValue{attribute.String("request.id", id)}
+		}),
+	), exporter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("TestInit_WithContextAttributeExtractor")
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-42")
+
+	// when
+	st, err := sp.NewWithContext(ctx, mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	_, span := st.StartSpan("operation")
+	span.End()
+
+	mock.runCleanups()
+
+	// then
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	for _, s := range spans {
+		attrs := make(map[attribute.Key]attribute.Value)
+		for _, attr := range s.Attributes {
+			attrs[attr.Key] = attr.Value
+		}
+
+		if got := attrs["request.id"].AsString(); got != "req-42" {
+			t.Errorf("expected request.id %q on span %q, got %q", "req-42", s.Name, got)
+		}
+	}
+}
+
+func TestInit_WithResource(t *testing.T) {
+	t.Parallel()
+
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+	res := resource.NewSchemaless(attribute.String("cloud.region", "eu-west-1"))
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithSpanExporter(exporter),
+		spectra.WithResource(res),
+		spectra.WithGlobalProviders(false),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("TestInit_WithResource")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	mock.runCleanups()
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	attrs := spans[0].Resource.Set()
+
+	if region, _ := attrs.Value("cloud.region"); region.AsString() != "eu-west-1" {
+		t.Errorf("expected cloud.region %q, got %q", "eu-west-1", region.AsString())
+	}
+
+	if name, _ := attrs.Value("service.name"); name.AsString() != "test-service" {
+		t.Errorf("expected service.name %q, got %q", "test-service", name.AsString())
+	}
+
+	if attrs.HasValue("test.framework") {
+		t.Error("expected the custom resource to be used without spectra's attributes")
+	}
+}
+
+func TestInit_WithProcessRuntimeDetectors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    []spectra.Option
+		wantPID bool
+	}{
+		{name: "enabled", opts: []spectra.Option{spectra.WithProcessRuntimeDetectors()}, wantPID: true},
+		{name: "disabled", wantPID: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			exporter := tracetest.NewInMemoryExporter()
+			opts := append([]spectra.Option{
+				spectra.WithServiceName("test"),
+				spectra.WithSpanExporter(exporter),
+				spectra.WithGlobalProviders(false),
+				spectra.WithoutMetrics(),
+			}, tt.opts...)
+
+			sp, err := spectra.Init(opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			defer func() {
+				err := sp.Shutdown()
+				if err != nil {
+					t.Errorf("unexpected shutdown error: %v", err)
+				}
+			}()
+
+			mock := newMockTB(tt.name)
+
+			_, err = sp.New(mock)
+			if err != nil {
+				t.Fatalf("failed to create test: %v", err)
+			}
+
+			// when
+			mock.runCleanups()
+
+			err = sp.Flush(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected flush error: %v", err)
+			}
+
+			// then
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+
+			pid, ok := spans[0].Resource.Set().Value("process.pid")
+			if ok != tt.wantPID {
+				t.Fatalf("expected process.pid present %v, got %v", tt.wantPID, ok)
+			}
+
+			if ok && pid.AsInt64() != int64(os.Getpid()) {
+				t.Errorf("expected process.pid %d, got %d", os.Getpid(), pid.AsInt64())
+			}
+		})
+	}
+}
+
+func TestInit_WithSpanNamer(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(exporter),
+		spectra.WithSpanNamer(strings.ToUpper),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// when
+	t.Run("named", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Run("subtest", func(_ *spectra.T) {})
+	})
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	testNames := make(map[string]string)
+
+	for _, span := range exporter.GetSpans() {
+		for _, attr := range span.Attributes {
+			if attr.Key == "test.name" {
+				testNames[span.Name] = attr.Value.AsString()
+			}
+		}
+	}
+
+	want := map[string]string{
+		"TESTINIT_WITHSPANNAMER/NAMED":         "TestInit_WithSpanNamer/named",
+		"TESTINIT_WITHSPANNAMER/NAMED/SUBTEST": "TestInit_WithSpanNamer/named/subtest",
+	}
+	if !maps.Equal(testNames, want) {
+		t.Errorf("expected spans with test names %v, got %v", want, testNames)
+	}
+}
+
+func TestInit_WithGlobalProvidersDisabled(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	globalTP := sdktrace.NewTracerProvider()
+	otel.SetTracerProvider(globalTP)
+
+	defer func() { _ = globalTP.Shutdown(context.Background()) }()
+
+	globalPropagator := propagation.TraceContext{}
+	otel.SetTextMapPropagator(globalPropagator)
+
+	firstSocket, firstCollector := startUnixCollector(t)
+	secondSocket, secondCollector := startUnixCollector(t)
+
+	first, err := spectra.Init(
+		spectra.WithServiceName("first"),
+		spectra.WithEndpoint("unix://"+firstSocket),
+		spectra.WithoutMetrics(),
+		spectra.WithGlobalProviders(false),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() { _ = first.Shutdown() }()
+
+	second, err := spectra.Init(
+		spectra.WithServiceName("second"),
+		spectra.WithEndpoint("unix://"+secondSocket),
+		spectra.WithoutMetrics(),
+		spectra.WithGlobalProviders(false),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() { _ = second.Shutdown() }()
+
+	// when
+	for name, sp := range map[string]*spectra.Spectra{"in-first": first, "in-second": second} {
+		mock := newMockTB(name)
+
+		_, err = sp.New(mock)
+		if err != nil {
+			t.Fatalf("failed to create test: %v", err)
+		}
+
+		mock.runCleanups()
+
+		err = sp.Flush(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected flush error: %v", err)
+		}
+	}
+
+	// then
+	if got := firstCollector.Names(); !slices.Equal(got, []string{"in-first"}) {
+		t.Errorf("expected first collector to receive only 'in-first', got %v", got)
+	}
+
+	if got := secondCollector.Names(); !slices.Equal(got, []string{"in-second"}) {
+		t.Errorf("expected second collector to receive only 'in-second', got %v", got)
+	}
+
+	if otel.GetTracerProvider() != globalTP {
+		t.Error("expected the global tracer provider to be left untouched")
+	}
+
+	if otel.GetTextMapPropagator() != globalPropagator {
+		t.Error("expected the global propagator to be left untouched")
+	}
+}
+
+func TestInit_WithGRPCDialOptions(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	socket, collector := startUnixCollector(t)
+
+	var calls atomic.Int32
+
+	interceptor := func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		calls.Add(1)
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("unix://"+socket),
+		spectra.WithoutMetrics(),
+		spectra.WithGRPCDialOptions(grpc.WithUnaryInterceptor(interceptor)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	if got := len(spectra.ResolvedConfig(sp).GRPCDialOptions); got != 1 {
+		t.Errorf("expected 1 dial option in config, got %d", got)
+	}
+
+	mock := newMockTB("intercepted")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	mock.runCleanups()
+
+	err = sp.Flush(context.Background())
+
+	// then
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	if calls.Load() == 0 {
+		t.Error("expected the export to go through the dial option's interceptor")
+	}
+
+	if got := collector.Names(); !slices.Equal(got, []string{"intercepted"}) {
+		t.Errorf("expected collector to receive 'intercepted', got %v", got)
+	}
+}
+
+func TestInit_WithGRPCDialOptionsNonGRPC(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// when
+	_, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithTracesEndpoint("grpc://localhost:4317"),
+		spectra.WithMetricsEndpoint("http://localhost:4318"),
+		spectra.WithGRPCDialOptions(grpc.WithUserAgent("spectra-test")),
+	)
+
+	// then
+	if !errors.Is(err, spectra.ErrDialOptionsRequireGRPC) {
+		t.Errorf("expected ErrDialOptionsRequireGRPC, got %v", err)
+	}
+}
+
+func TestInit_WithTLSConfig(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	for _, endpoint := range []string{"grpc://localhost:4317", "https://localhost:4318"} {
+		t.Run(endpoint, func(t *testing.T) {
+			// given/when
+			sp, err := spectra.Init(
+				spectra.WithServiceName("test-service"),
+				spectra.WithEndpoint(endpoint),
+				spectra.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS13}),
+				spectra.WithoutMetrics(),
+			)
+			// then
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = sp.Shutdown()
+			if err != nil {
+				t.Errorf("unexpected shutdown error: %v", err)
+			}
+		})
+	}
+}
+
+func TestInit_HTTPEndpointWithTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	// given
+	requests := make(chan string, 1)
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case requests <- r.URL.Path:
+		default:
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	transport, ok := srv.Client().Transport.(*http.Transport)
+	if !ok {
+		t.Fatal("expected *http.Transport")
+	}
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("http://"+srv.Listener.Addr().String()),
+		spectra.WithTLSConfig(transport.TLSClientConfig),
+		spectra.WithGlobalProviders(false),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("TestInit_HTTPEndpointWithTLSConfig")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	mock.runCleanups()
+
+	// when
+	err = sp.Flush(context.Background())
+
+	// then
+	if err != nil {
+		t.Fatalf("expected export over TLS to succeed, got %v", err)
+	}
+
+	select {
+	case path := <-requests:
+		if path != "/v1/traces" {
+			t.Errorf("expected request to /v1/traces, got %q", path)
+		}
+	default:
+		t.Error("expected the TLS server to receive the trace export")
+	}
+}
+
+func TestInit_InsecureWithTLSConfig(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given/when
+	_, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithInsecure(),
+		spectra.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS13}),
+	)
+
+	// then
+	if !errors.Is(err, spectra.ErrInsecureWithTLS) {
+		t.Errorf("expected ErrInsecureWithTLS, got %v", err)
+	}
+}
+
+func TestInit_WithCACertFile(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	certFile, _ := writeTestCertificate(t)
+
+	// when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("https://localhost:4318"),
+		spectra.WithCACertFile(certFile),
+		spectra.WithoutMetrics(),
+	)
+	// then
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	tlsConfig := spectra.ResolvedConfig(sp).TLSConfig
+	if tlsConfig == nil || tlsConfig.RootCAs == nil {
+		t.Fatal("expected TLS config with RootCAs")
+	}
+}
+
+func TestInit_WithCACertFileErrors(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	invalidFile := filepath.Join(t.TempDir(), "invalid.pem")
+
+	err := os.WriteFile(invalidFile, []byte("not a certificate"), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{name: "missing_file", path: filepath.Join(t.TempDir(), "missing.pem"), wantErr: fs.ErrNotExist},
+		{name: "invalid_pem", path: invalidFile, wantErr: spectra.ErrInvalidCACert},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given/when
+			_, err := spectra.Init(
+				spectra.WithServiceName("test-service"),
+				spectra.WithEndpoint("grpc://localhost:4317"),
+				spectra.WithCACertFile(tt.path),
+			)
+
+			// then
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestInit_WithClientCertificate(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	certFile, keyFile := writeTestCertificate(t)
+
+	// when - combined with a CA file
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithCACertFile(certFile),
+		spectra.WithClientCertificate(certFile, keyFile),
+		spectra.WithoutMetrics(),
+	)
+	// then
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	tlsConfig := spectra.ResolvedConfig(sp).TLSConfig
+	if tlsConfig == nil {
+		t.Fatal("expected TLS config")
+	}
+
+	if len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("expected 1 client certificate, got %d", len(tlsConfig.Certificates))
+	}
+
+	leaf, err := x509.ParseCertificate(tlsConfig.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	if leaf.Subject.CommonName != "spectra-test" {
+		t.Errorf("expected certificate CN 'spectra-test', got %q", leaf.Subject.CommonName)
+	}
+
+	if tlsConfig.RootCAs == nil {
+		t.Error("expected RootCAs from CA file to be preserved")
+	}
+}
+
+func TestInit_WithClientCertificateMissing(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	dir := t.TempDir()
+
+	// when
+	_, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithClientCertificate(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")),
+	)
+
+	// then
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+}
+
+func TestInit_DefaultPropagator(t *testing.T) {
+	// Tests modify global propagator - cannot run in parallel.
+
+	// given/when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// then - trace context and baggage are both propagated.
+	fields := otel.GetTextMapPropagator().Fields()
+	for _, want := range []string{"traceparent", "baggage"} {
+		if !slices.Contains(fields, want) {
+			t.Errorf("expected propagator field %q, got %v", want, fields)
+		}
+	}
+}
+
+func TestInit_WithPropagator(t *testing.T) {
+	// Tests modify global propagator - cannot run in parallel.
+
+	// given/when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithPropagator(propagation.Baggage{}),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// then
+	fields := otel.GetTextMapPropagator().Fields()
+	if !slices.Equal(fields, []string{"baggage"}) {
+		t.Errorf("expected only baggage field, got %v", fields)
+	}
+}
+
+func TestInit_WithLogger(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	var logged []string
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithStdoutExporter(),
+		spectra.WithoutMetrics(),
+		spectra.WithLogger(func(format string, args ...any) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// when - the SDK reports an internal error.
+	otel.Handle(errors.New("export failed"))
+
+	// then
+	if len(logged) != 1 || logged[0] != "spectra: export failed" {
+		t.Errorf("expected logger to receive 'spectra: export failed', got %q", logged)
+	}
+}
+
+func TestInit_DefaultLogger(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithStdoutExporter(),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// then
+	if spectra.ResolvedConfig(sp).Logger == nil {
+		t.Error("expected default logger")
+	}
+}
+
+func TestInit_WithLogExporter(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithStdoutExporter(),
+		spectra.WithoutMetrics(),
+		spectra.WithLogExporter(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// when
+	err = sp.Shutdown()
+
+	// then
+	if err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+
+	if !spectra.ResolvedConfig(sp).ExportLogs {
+		t.Error("expected log export to be enabled")
+	}
+}
+
+func TestInit_DisableTraces(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	endpoint, _ := startGRPCCollector(t)
+
+	// when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint(endpoint),
+		spectra.WithInsecure(),
+		spectra.WithoutTraces(),
+	)
+	// then - should return a valid Spectra instance even with traces disabled.
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sp == nil {
+		t.Error("expected non-nil Spectra instance")
+	}
+
+	err = sp.Shutdown()
+	if err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestInit_DisableMetrics(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given/when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test-service"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithoutMetrics(),
+	)
+	// then - should return a valid Spectra instance even with metrics disabled.
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sp == nil {
+		t.Error("expected non-nil Spectra instance")
+	}
+
+	err = sp.Shutdown()
+	if err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestInit_DisableLogs(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, _ := setupTestTracer(t)
+	endpoint, _ := startGRPCCollector(t)
+
+	sp, err := spectra.Init(
 		spectra.WithServiceName("test-service"),
-		spectra.WithEndpoint("https://localhost:4318"),
+		spectra.WithEndpoint(endpoint),
+		spectra.WithInsecure(),
+		spectra.WithoutLogs(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	// when
+	t.Run("logs_disabled", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		st.Log("this should not appear as span event")
+	})
+
+	// then - span should exist but without log events.
+	spans := exporter.GetSpans()
+
+	for _, s := range spans {
+		if s.Name == "TestInit_DisableLogs/logs_disabled" {
+			for _, event := range s.Events {
+				if event.Name == "log" {
+					t.Error("expected no log events when DisableLogs is true")
+				}
+			}
+
+			return
+		}
+	}
+}
+
+func TestInit_DisabledIgnoresOtherOptions(t *testing.T) {
+	t.Parallel()
+
+	// given
+	hookCalled := false
+
+	sp, err := spectra.Init(
+		spectra.WithDisabled(),
+		spectra.WithTestEndHook(func(spectra.TestResult) { hookCalled = true }),
+		spectra.WithGoroutineLeakCheck(true),
+		spectra.WithMemoryProfiling(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("TestInit_DisabledIgnoresOtherOptions")
+	release := make(chan struct{})
+
+	defer close(release)
+
+	// when
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	go func() { <-release }()
+
+	start := time.Now()
+
+	mock.runCleanups()
+
+	// then
+	if mock.failed {
+		t.Error("expected the leaked goroutine not to fail the test when disabled")
+	}
+
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Errorf("expected no goroutine leak settle delay when disabled, took %v", elapsed)
+	}
+
+	if hookCalled {
+		t.Error("expected the test end hook not to be called when disabled")
+	}
+}
+
+func TestInit_Disabled(t *testing.T) {
+	t.Parallel()
+
+	// given - no service name or endpoint
+	sp, err := spectra.Init(spectra.WithDisabled())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// when
+	var subtestRan bool
+
+	t.Run("disabled", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("expected New to work when disabled, got %v", err)
+		}
+
+		st.Log("message")
+		st.Logf("formatted %d", 1)
+		st.SetAttributes(attribute.String("key", "value"))
+		st.AddEvent("event")
+		st.RecordValue("rows.processed", 1)
+		st.AssertEqual("answer", 42, 42)
+		st.AssertNoError(nil)
+
+		member, memberErr := baggage.NewMember("key", "value")
+		if memberErr != nil {
+			innerT.Fatalf("failed to create baggage member: %v", memberErr)
+		}
+
+		st.SetBaggage(member)
+
+		st.Setup(func(_ context.Context) {})
+		st.Teardown(func(_ context.Context) {})
+
+		_, span := st.StartSpan("operation")
+		span.End()
+
+		st.Run("subtest", func(_ *spectra.T) {
+			subtestRan = true
+		})
+
+		if st.Span().SpanContext().IsValid() {
+			innerT.Error("expected a no-op test span")
+		}
+	})
+
+	// then
+	if !subtestRan {
+		t.Error("expected subtest to run")
+	}
+
+	mock := newMockTB("failing")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("expected New to work when disabled, got %v", err)
+	}
+
+	st.Error("boom")
+	mock.runCleanups()
+
+	if !mock.Failed() {
+		t.Error("expected Error to still fail the test")
+	}
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Errorf("expected flush to succeed, got %v", err)
+	}
+
+	err = sp.Shutdown()
+	if err != nil {
+		t.Errorf("expected shutdown to succeed, got %v", err)
+	}
+}
+
+func TestSpectraInit(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	endpoint, _ := startGRPCCollector(t)
+
+	// when
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint(endpoint),
+		spectra.WithInsecure(),
+	)
+	// then
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sp == nil {
+		t.Fatal("expected non-nil *Spectra")
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+}
+
+func TestSpectraShutdownIdempotent(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithoutMetrics(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// when - call Shutdown twice
+	firstErr := sp.Shutdown()
+	secondErr := sp.Shutdown()
+
+	// then
+	if firstErr != nil {
+		t.Errorf("expected nil error from first shutdown, got %v", firstErr)
+	}
+
+	if secondErr != nil {
+		t.Errorf("expected nil error from second shutdown, got %v", secondErr)
+	}
+}
+
+func TestSpectraShutdownReturnsExportError(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given - the collector's listener is closed, so the final metric export fails.
+	listener, err := (&net.ListenConfig{}).Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen on tcp: %v", err)
+	}
+
+	endpoint := "http://" + listener.Addr().String()
+
+	err = listener.Close()
+	if err != nil {
+		t.Fatalf("failed to close listener: %v", err)
+	}
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint(endpoint),
+		spectra.WithoutTraces(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// when
+	firstErr := sp.Shutdown()
+	secondErr := sp.Shutdown()
+
+	// then
+	if firstErr == nil {
+		t.Error("expected error from first shutdown without a collector")
+	}
+
+	if secondErr != nil {
+		t.Errorf("expected nil error from second shutdown, got %v", secondErr)
+	}
+}
+
+func TestSpectra_Flush(t *testing.T) {
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.NewWithExporter(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+	), exporter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mock := newMockTB("flush")
+
+	_, err = sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	mock.runCleanups()
+
+	// when
+	err = sp.Flush(context.Background())
+
+	// then
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "flush" {
+		t.Fatalf("expected flushed 'flush' span, got %v", spans)
+	}
+
+	err = sp.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	err = sp.Flush(context.Background())
+	if !errors.Is(err, spectra.ErrAlreadyShutdown) {
+		t.Errorf("expected ErrAlreadyShutdown after shutdown, got %v", err)
+	}
+}
+
+func TestSpectra_Tracer(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	t.Run("configured", func(t *testing.T) {
+		// given
+		exporter := tracetest.NewInMemoryExporter()
+
+		sp, err := spectra.NewWithExporter(spectra.NewConfig(
+			spectra.WithServiceName("test"),
+			spectra.WithEndpoint("grpc://localhost:4317"),
+		), exporter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// when
+		_, span := sp.Tracer().Start(context.Background(), "helper")
+		span.End()
+
+		err = sp.Flush(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected flush error: %v", err)
+		}
+
+		// then
+		spans := exporter.GetSpans()
+		if len(spans) != 1 || spans[0].Name != "helper" {
+			t.Errorf("expected exported 'helper' span, got %v", spans)
+		}
+
+		err = sp.Shutdown()
+		if err != nil {
+			t.Fatalf("unexpected shutdown error: %v", err)
+		}
+
+		_, span = sp.Tracer().Start(context.Background(), "after-shutdown")
+		defer span.End()
+
+		if span.SpanContext().IsValid() {
+			t.Error("expected no-op span after shutdown")
+		}
+	})
+
+	t.Run("traces_disabled", func(t *testing.T) {
+		// given
+		_, sp := setupTestTracer(t)
+
+		// when
+		_, span := sp.Tracer().Start(context.Background(), "helper")
+		defer span.End()
+
+		// then
+		if span.SpanContext().IsValid() {
+			t.Error("expected no-op span when traces are disabled")
+		}
+	})
+}
+
+func TestNewReturnsError(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given - nil Spectra, not initialized
+	var sp *spectra.Spectra
+
+	// when
+	_, err := sp.New(t)
+
+	// then
+	if !errors.Is(err, spectra.ErrNotInitialized) {
+		t.Errorf("expected ErrNotInitialized, got %v", err)
+	}
+}
+
+func TestNewAfterShutdown(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	endpoint, _ := startGRPCCollector(t)
+
+	sp, err := spectra.Init(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint(endpoint),
+		spectra.WithInsecure(),
 	)
-	// then - should return a valid Spectra instance.
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if sp == nil {
-		t.Error("expected non-nil Spectra instance")
+	// when - shutdown then try to create new test
+	err = sp.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
 	}
 
-	sp.Shutdown()
+	_, err = sp.New(t)
+
+	// then
+	if !errors.Is(err, spectra.ErrAlreadyShutdown) {
+		t.Errorf("expected ErrAlreadyShutdown, got %v", err)
+	}
 }
 
-func TestInit_HTTPS_Insecure(t *testing.T) {
+func TestInitMetrics(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
-	// given/when
+	// given
+	endpoint, _ := startGRPCCollector(t)
+
+	// when - Init with metrics enabled (default)
 	sp, err := spectra.Init(
-		spectra.WithServiceName("test-service"),
-		spectra.WithEndpoint("https://localhost:4318"),
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint(endpoint),
 		spectra.WithInsecure(),
+		spectra.WithoutTraces(), // disable traces to isolate metrics
 	)
-	// then - should return a valid Spectra instance.
+	// then - should succeed (metrics initialization happens internally)
+	if err != nil {
+		t.Fatalf("unexpected error during init with metrics: %v", err)
+	}
+
+	if sp == nil {
+		t.Fatal("expected non-nil Spectra instance")
+	}
+
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+}
+
+func TestInitMetrics_Reinit(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given - a first instance that is used and shut down.
+	cfg := spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+	)
+	firstReader := sdkmetric.NewManualReader()
+
+	first, err := spectra.NewWithMetricReader(cfg, firstReader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	firstMock := newMockTB("first")
+
+	_, err = first.New(firstMock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	firstMock.runCleanups()
+
+	if got := countedTestNames(t, firstReader, "test.count"); !slices.Equal(got, []string{"first"}) {
+		t.Fatalf("expected first instance to count 'first', got %v", got)
+	}
+
+	err = first.Shutdown()
+	if err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	// when - a second instance is initialized and used.
+	secondReader := sdkmetric.NewManualReader()
+
+	second, err := spectra.NewWithMetricReader(cfg, secondReader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() { _ = second.Shutdown() }()
+
+	secondMock := newMockTB("second")
+
+	_, err = second.New(secondMock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	secondMock.runCleanups()
+
+	// then - metrics flow to the new provider only.
+	if got := countedTestNames(t, secondReader, "test.count"); !slices.Equal(got, []string{"second"}) {
+		t.Errorf("expected second instance to count only 'second', got %v", got)
+	}
+}
+
+func TestT_Run_SubtestErrorStatus(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	parent := newMockTB("parent")
+	child := newMockTB("parent/child")
+
+	st, err := sp.New(parent)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	subST := spectra.NewSubtest(st, child)
+
+	// when - the TB has not reported the failure yet when the subtest ends.
+	subST.Error("boom")
+
+	child.failed = false
+	child.runCleanups()
+
+	// then
+	var targetSpan tracetest.SpanStub
+
+	for _, s := range exporter.GetSpans() {
+		if s.Name == "parent/child" {
+			targetSpan = s
+
+			break
+		}
+	}
+
+	if targetSpan.Status.Code != codes.Error {
+		t.Errorf("expected subtest span status Error, got %v", targetSpan.Status.Code)
+	}
+
+	if targetSpan.Status.Description != "subtest failed" {
+		t.Errorf("expected description %q, got %q", "subtest failed", targetSpan.Status.Description)
+	}
+}
+
+func TestT_WithTimeout(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_WithTimeout")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when - the operation outlasts the timeout.
+	st.WithTimeout(10 * time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	mock.runCleanups()
+
+	// then
+	if !mock.Failed() {
+		t.Error("expected timed out test to fail")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("expected span status Error, got %v", spans[0].Status.Code)
+	}
+
+	attrs := eventAttributes(spans[0], "timeout")
+	if attrs == nil {
+		t.Fatal("expected timeout event")
+	}
+
+	if got := attrs["test.timeout"].AsString(); got != "10ms" {
+		t.Errorf("expected test.timeout 10ms, got %q", got)
+	}
+}
+
+func TestT_WithTimeout_ForceSample(t *testing.T) {
+	t.Parallel()
+
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.NewWithExporter(spectra.NewConfig(
+		spectra.WithServiceName("test"),
+		spectra.WithEndpoint("grpc://localhost:4317"),
+		spectra.WithSampler(sdktrace.NeverSample()),
+	), exporter)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if sp == nil {
-		t.Error("expected non-nil Spectra instance")
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	mock := newMockTB("TestT_WithTimeout_ForceSample")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when - the timer reads the span while ForceSample replaces it; run
+	// with -race to detect unsynchronized access.
+	st.WithTimeout(time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	st.ForceSample()
+	mock.runCleanups()
+
+	// then
+	if !mock.Failed() {
+		t.Error("expected timed out test to fail")
+	}
+}
+
+func TestT_WithTimeoutNotExceeded(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_WithTimeoutNotExceeded")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	st.WithTimeout(time.Hour)
+	mock.runCleanups()
+
+	// then
+	if mock.Failed() {
+		t.Error("expected test within its timeout to pass")
+	}
+
+	if eventAttributes(exporter.GetSpans()[0], "timeout") != nil {
+		t.Error("expected no timeout event")
+	}
+}
+
+func TestT_ContextCancelledWhenTestEnds(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	_, sp := setupTestTracer(t)
+	done := make(chan struct{})
+
+	// when
+	t.Run("background", func(innerT *testing.T) {
+		st, err := sp.New(innerT)
+		if err != nil {
+			innerT.Fatalf("failed to create test: %v", err)
+		}
+
+		go func() {
+			<-st.Context().Done()
+			close(done)
+		}()
+	})
+
+	// then
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the test context to be cancelled after the test ended")
+	}
+}
+
+func TestT_ContextCancelledOnFatal(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	_, sp := setupTestTracer(t)
+	mock := newMockTB("fatal")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	defer mock.runCleanups()
+
+	// when
+	st.Fatal("stop")
+
+	// then
+	if st.Context().Err() == nil {
+		t.Error("expected the test context to be cancelled by Fatal")
+	}
+}
+
+func TestT_RecordError(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_RecordError")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	st.RecordError(errors.New("connection refused"), attribute.String("db.system", "postgres"))
+	mock.runCleanups()
+
+	// then
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("expected span status Error, got %v", spans[0].Status.Code)
+	}
+
+	if !mock.failed {
+		t.Error("expected mock.failed to be true after RecordError()")
+	}
+
+	attrs := eventAttributes(spans[0], "exception")
+	if attrs == nil {
+		t.Fatal("expected exception event not found")
+	}
+
+	if attrs["exception.message"].AsString() != "connection refused" {
+		t.Errorf("expected exception.message %q, got %q", "connection refused", attrs["exception.message"].AsString())
+	}
+
+	if attrs["exception.stacktrace"].AsString() == "" {
+		t.Error("expected exception.stacktrace attribute")
+	}
+
+	if attrs["db.system"].AsString() != "postgres" {
+		t.Errorf("expected db.system %q, got %q", "postgres", attrs["db.system"].AsString())
+	}
+}
+
+func TestT_RecordError_Nil(t *testing.T) {
+	// Tests modify global tracer provider - cannot run in parallel.
+
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_RecordError_Nil")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	st.RecordError(nil)
+	mock.runCleanups()
+
+	// then
+	if mock.failed {
+		t.Error("expected RecordError(nil) not to fail the test")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].Status.Code == codes.Error {
+		t.Error("expected span status not to be Error")
+	}
+
+	if attrs := eventAttributes(spans[0], "exception"); attrs != nil {
+		t.Errorf("expected no exception event, got %v", attrs)
 	}
+}
+
+// recordOnlySampler records every span without sampling it, so processors
+// see spans that are never exported.
+type recordOnlySampler struct{}
 
-	sp.Shutdown()
+func (recordOnlySampler) ShouldSample(sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.SamplingResult{Decision: sdktrace.RecordOnly}
 }
 
-func TestInit_InvalidEndpoint(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+func (recordOnlySampler) Description() string { return "RecordOnly" }
 
-	// given/when - endpoint without scheme
-	_, err := spectra.Init(
-		spectra.WithServiceName("test-service"),
-		spectra.WithEndpoint("localhost:4317"),
-	)
+func TestT_ForceSample_EndsReplacedSpan(t *testing.T) {
+	t.Parallel()
 
-	// then - should return error
-	if err == nil {
-		t.Fatal("expected error for endpoint without scheme")
-	}
-}
+	// given
+	var order []string
 
-func TestInit_DisableTraces(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+	processor := &countingProcessor{name: "counting", order: &order}
+	exporter := tracetest.NewInMemoryExporter()
 
-	// given/when
 	sp, err := spectra.Init(
-		spectra.WithServiceName("test-service"),
-		spectra.WithEndpoint("grpc://localhost:4317"),
-		spectra.WithoutTraces(),
+		spectra.WithServiceName("test"),
+		spectra.WithSpanExporter(exporter),
+		spectra.WithSpanProcessor(processor),
+		spectra.WithSampler(recordOnlySampler{}),
+		spectra.WithoutMetrics(),
+		spectra.WithGlobalProviders(false),
 	)
-	// then - should return a valid Spectra instance even with traces disabled.
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if sp == nil {
-		t.Error("expected non-nil Spectra instance")
-	}
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
 
-	sp.Shutdown()
-}
+	mock := newMockTB("TestT_ForceSample_EndsReplacedSpan")
 
-func TestInit_DisableMetrics(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
 
-	// given/when
-	sp, err := spectra.Init(
-		spectra.WithServiceName("test-service"),
-		spectra.WithEndpoint("grpc://localhost:4317"),
-		spectra.WithoutMetrics(),
-	)
-	// then - should return a valid Spectra instance even with metrics disabled.
+	// when
+	st.ForceSample()
+	mock.runCleanups()
+
+	err = sp.Flush(context.Background())
 	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+		t.Fatalf("unexpected flush error: %v", err)
 	}
 
-	if sp == nil {
-		t.Error("expected non-nil Spectra instance")
+	// then
+	if processor.started != 2 || len(order) != 2 {
+		t.Errorf("expected 2 spans to start and end, got %d starts and %d ends", processor.started, len(order))
 	}
 
-	sp.Shutdown()
+	if spans := exporter.GetSpans(); len(spans) != 1 {
+		t.Errorf("expected only the forced span to be exported, got %d spans", len(spans))
+	}
 }
 
-func TestInit_DisableLogs(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+func TestT_ForceSample_ConcurrentSpanAccess(t *testing.T) {
+	t.Parallel()
 
 	// given
-	exporter, _ := setupTestTracer(t)
+	exporter := tracetest.NewInMemoryExporter()
 
-	sp, err := spectra.Init(
-		spectra.WithServiceName("test-service"),
+	sp, err := spectra.NewWithExporter(spectra.NewConfig(
+		spectra.WithServiceName("test"),
 		spectra.WithEndpoint("grpc://localhost:4317"),
-		spectra.WithoutLogs(),
-	)
+		spectra.WithSampler(sdktrace.NeverSample()),
+	), exporter)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	defer sp.Shutdown()
-
-	// when
-	t.Run("logs_disabled", func(innerT *testing.T) {
-		st, err := sp.New(innerT)
+	defer func() {
+		err := sp.Shutdown()
 		if err != nil {
-			innerT.Fatalf("failed to create test: %v", err)
+			t.Errorf("unexpected shutdown error: %v", err)
 		}
+	}()
 
-		st.Log("this should not appear as span event")
-	})
+	mock := newMockTB("TestT_ForceSample_ConcurrentSpanAccess")
 
-	// then - span should exist but without log events.
-	spans := exporter.GetSpans()
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
 
-	for _, s := range spans {
-		if s.Name == "TestInit_DisableLogs/logs_disabled" {
-			for _, event := range s.Events {
-				if event.Name == "log" {
-					t.Error("expected no log events when DisableLogs is true")
-				}
-			}
+	done := make(chan struct{})
 
-			return
+	// when - run with -race to detect unsynchronized access to the span.
+	go func() {
+		defer close(done)
+
+		for i := range 100 {
+			st.AddEvent("progress", attribute.Int("step", i))
+			st.SetAttributes(attribute.Int("steps", i))
+			_ = st.TraceID()
 		}
+	}()
+
+	st.ForceSample()
+	<-done
+
+	mock.runCleanups()
+
+	// then
+	if !st.Span().SpanContext().IsSampled() {
+		t.Error("expected the forced span to be sampled")
 	}
 }
 
-func TestSpectraInit(t *testing.T) {
-	// Tests modify global tracer provider - cannot run in parallel.
+func TestT_ForceSample(t *testing.T) {
+	t.Parallel()
 
-	// given/when
-	sp, err := spectra.Init(
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+
+	sp, err := spectra.NewWithExporter(spectra.NewConfig(
 		spectra.WithServiceName("test"),
 		spectra.WithEndpoint("grpc://localhost:4317"),
-	)
-	// then
+		spectra.WithSamplingRatio(0),
+	), exporter)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if sp == nil {
-		t.Fatal("expected non-nil *Spectra")
+	defer func() {
+		err := sp.Shutdown()
+		if err != nil {
+			t.Errorf("unexpected shutdown error: %v", err)
+		}
+	}()
+
+	forced := newMockTB("forced")
+	unforced := newMockTB("unforced")
+
+	forcedST, err := sp.New(forced)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	_, err = sp.New(unforced)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	// when
+	forcedST.ForceSample()
+
+	_, child := forcedST.StartSpan("child")
+	child.End()
+
+	forced.runCleanups()
+	unforced.runCleanups()
+
+	err = sp.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	// then
+	spans := make(map[string]tracetest.SpanStub)
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+
+	if len(spans) != 2 {
+		t.Fatalf("expected only the forced test and its child to be exported, got %v", slices.Collect(maps.Keys(spans)))
+	}
+
+	testSpan, ok := spans["forced"]
+	if !ok {
+		t.Fatal("expected forced test span to be exported")
+	}
+
+	attrs := attribute.NewSet(testSpan.Attributes...)
+	if name, _ := attrs.Value("test.name"); name.AsString() != "forced" {
+		t.Errorf("expected test.name %q on the restarted span, got %q", "forced", name.AsString())
+	}
+
+	if status, _ := attrs.Value("test.status"); status.AsString() != "pass" {
+		t.Errorf("expected test.status %q, got %q", "pass", status.AsString())
+	}
+
+	if !testSpan.StartTime.Equal(spectra.StartTime(forcedST)) {
+		t.Errorf("expected the restarted span to keep the test start time")
 	}
 
-	defer sp.Shutdown()
+	if spans["child"].Parent.SpanID() != testSpan.SpanContext.SpanID() {
+		t.Error("expected the child span to be a child of the forced test span")
+	}
 }
 
-func TestSpectraShutdownIdempotent(t *testing.T) {
+func TestT_AddLink(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
-	sp, err := spectra.Init(
-		spectra.WithServiceName("test"),
-		spectra.WithEndpoint("grpc://localhost:4317"),
-	)
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_AddLink")
+
+	st, err := sp.New(mock)
 	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+		t.Fatalf("failed to create test: %v", err)
 	}
 
-	// when - call Shutdown twice
-	sp.Shutdown()
-	sp.Shutdown() // should not panic
+	linked := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
 
-	// then - test passes if no panic occurred
+	// when
+	st.AddLink(linked, attribute.String("link.reason", "replay"))
+	st.AddLink(trace.SpanContext{})
+	mock.runCleanups()
+
+	// then
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	links := spans[0].Links
+	if len(links) != 1 {
+		t.Fatalf("expected 1 link, invalid span contexts ignored, got %d", len(links))
+	}
+
+	if !links[0].SpanContext.Equal(linked) {
+		t.Errorf("expected link to %v, got %v", linked, links[0].SpanContext)
+	}
+
+	if len(links[0].Attributes) != 1 || links[0].Attributes[0].Value.AsString() != "replay" {
+		t.Errorf("expected link attribute link.reason=replay, got %v", links[0].Attributes)
+	}
 }
 
-func TestNewReturnsError(t *testing.T) {
+func TestT_Retry(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
-	// given - nil Spectra, not initialized
-	var sp *spectra.Spectra
+	// given
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_Retry")
+
+	st, err := sp.New(mock)
+	if err != nil {
+		t.Fatalf("failed to create test: %v", err)
+	}
+
+	calls := 0
 
 	// when
-	_, err := sp.New(t)
+	passed := st.Retry(3, func(_ *spectra.T) error {
+		calls++
+		if calls == 1 {
+			return errors.New("connection refused")
+		}
+
+		return nil
+	})
+
+	mock.runCleanups()
 
 	// then
-	if !errors.Is(err, spectra.ErrNotInitialized) {
-		t.Errorf("expected ErrNotInitialized, got %v", err)
+	if !passed || calls != 2 {
+		t.Fatalf("expected to pass on the second attempt, got passed=%v after %d calls", passed, calls)
+	}
+
+	if mock.failed {
+		t.Error("expected the test not to fail when a retry passes")
+	}
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+
+	wantStatus := map[string]codes.Code{
+		"TestT_Retry/attempt-1": codes.Error,
+		"TestT_Retry/attempt-2": codes.Ok,
+	}
+	wantAttemptStatus := map[string]string{
+		"TestT_Retry/attempt-1": "fail",
+		"TestT_Retry/attempt-2": "pass",
+	}
+
+	for i, name := range []string{"TestT_Retry/attempt-1", "TestT_Retry/attempt-2"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("expected attempt span %q not found", name)
+
+			continue
+		}
+
+		if span.Status.Code != wantStatus[name] {
+			t.Errorf("expected %q status %v, got %v", name, wantStatus[name], span.Status.Code)
+		}
+
+		attrs := attribute.NewSet(span.Attributes...)
+		if attempt, _ := attrs.Value("test.attempt"); attempt.AsInt64() != int64(i+1) {
+			t.Errorf("expected %q test.attempt %d, got %v", name, i+1, attempt.Emit())
+		}
+
+		if status, _ := attrs.Value("test.attempt.status"); status.AsString() != wantAttemptStatus[name] {
+			t.Errorf("expected %q test.attempt.status %q, got %q", name, wantAttemptStatus[name], status.AsString())
+		}
+
+		if attrs.HasValue("test.status") {
+			t.Errorf("expected %q to have no test.status, so reports don't count it as a test", name)
+		}
+	}
+
+	testAttrs := attribute.NewSet(spans["TestT_Retry"].Attributes...)
+	if flaky, _ := testAttrs.Value("test.flaky"); !flaky.AsBool() {
+		t.Error("expected test.flaky=true on the test span")
 	}
 }
 
-func TestNewAfterShutdown(t *testing.T) {
+func TestT_Retry_FailureMethods(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
 	// given
-	sp, err := spectra.Init(
-		spectra.WithServiceName("test"),
-		spectra.WithEndpoint("grpc://localhost:4317"),
-	)
+	exporter, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_Retry_FailureMethods")
+
+	st, err := sp.New(mock)
 	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+		t.Fatalf("failed to create test: %v", err)
 	}
 
-	// when - shutdown then try to create new test
-	sp.Shutdown()
-	_, err = sp.New(t)
+	calls := 0
 
-	// then
-	if !errors.Is(err, spectra.ErrAlreadyShutdown) {
-		t.Errorf("expected ErrAlreadyShutdown, got %v", err)
+	var cleanups []int
+
+	// when - attempt 1 fails with Errorf, attempt 2 with Fatal, attempt 3
+	// passes.
+	passed := st.Retry(3, func(st *spectra.T) error {
+		calls++
+		attempt := calls
+
+		st.Cleanup(func() { cleanups = append(cleanups, attempt) })
+
+		switch attempt {
+		case 1:
+			st.Errorf("expected %d, got %d", 200, 503)
+		case 2:
+			st.Fatal("connection refused")
+			t.Error("expected Fatal to end the attempt")
+		}
+
+		return nil
+	})
+
+	// then - the attempts' cleanups ran before the test's.
+	if !slices.Equal(cleanups, []int{1, 2, 3}) {
+		t.Errorf("expected each attempt's cleanup to run when it ends, got %v", cleanups)
+	}
+
+	mock.runCleanups()
+
+	if !passed || calls != 3 {
+		t.Fatalf("expected to pass on the third attempt, got passed=%v after %d calls", passed, calls)
+	}
+
+	if mock.failed {
+		t.Error("expected failed attempts not to fail the test")
+	}
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+
+	attrs := eventAttributes(spans["TestT_Retry_FailureMethods/attempt-1"], "exception")
+	if got := attrs["exception.message"].AsString(); !strings.Contains(got, "expected 200, got 503") {
+		t.Errorf("expected attempt 1 to record its error, got %q", got)
+	}
+
+	if code := spans["TestT_Retry_FailureMethods/attempt-2"].Status.Code; code != codes.Error {
+		t.Errorf("expected attempt 2 status Error, got %v", code)
+	}
+
+	testAttrs := attribute.NewSet(spans["TestT_Retry_FailureMethods"].Attributes...)
+	if flaky, _ := testAttrs.Value("test.flaky"); !flaky.AsBool() {
+		t.Error("expected test.flaky=true on the test span")
 	}
 }
 
-func TestInitMetrics(t *testing.T) {
+func TestT_Retry_NonPositiveAttempts(t *testing.T) {
 	// Tests modify global tracer provider - cannot run in parallel.
 
-	// given/when - Init with metrics enabled (default)
-	sp, err := spectra.Init(
-		spectra.WithServiceName("test"),
-		spectra.WithEndpoint("grpc://localhost:4317"),
-		spectra.WithoutTraces(), // disable traces to isolate metrics
-	)
-	// then - should succeed (metrics initialization happens internally)
+	// given
+	_, sp := setupTestTracer(t)
+	mock := newMockTB("TestT_Retry_NonPositiveAttempts")
+
+	st, err := sp.New(mock)
 	if err != nil {
-		t.Fatalf("unexpected error during init with metrics: %v", err)
+		t.Fatalf("failed to create test: %v", err)
 	}
 
-	if sp == nil {
-		t.Fatal("expected non-nil Spectra instance")
+	calls := 0
+
+	// when
+	passed := st.Retry(0, func(_ *spectra.T) error {
+		calls++
+
+		return nil
+	})
+
+	mock.runCleanups()
+
+	// then
+	if !passed || calls != 1 {
+		t.Errorf("expected a single passing attempt, got passed=%v after %d calls", passed, calls)
 	}
 
-	defer sp.Shutdown()
+	if mock.failed {
+		t.Error("expected the test not to fail")
+	}
 }
 
 func TestT_FailNow(t *testing.T) {
@@ -0,0 +1,58 @@
+// Package spectratest provides helpers for asserting on spans recorded with
+// tracetest.InMemoryExporter, for tests of spectra-instrumented code.
+package spectratest
+
+import (
+	"reflect"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// FindSpan returns the first span in spans named name, and whether one was
+// found.
+//
+// Example:
+//
+//	span, ok := spectratest.FindSpan(exporter.GetSpans(), "TestCheckout/act")
+//	if !ok {
+//	    t.Fatal("act span not found")
+//	}
+func FindSpan(spans tracetest.SpanStubs, name string) (tracetest.SpanStub, bool) {
+	for _, span := range spans {
+		if span.Name == name {
+			return span, true
+		}
+	}
+
+	return tracetest.SpanStub{}, false
+}
+
+// HasAttribute reports whether span has an attribute key equal to value.
+//
+// Example:
+//
+//	if !spectratest.HasAttribute(span, "test.status", attribute.StringValue("pass")) {
+//	    t.Error("expected passing test span")
+//	}
+func HasAttribute(span tracetest.SpanStub, key string, value attribute.Value) bool {
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value.Type() == value.Type() &&
+				reflect.DeepEqual(attr.Value.AsInterface(), value.AsInterface())
+		}
+	}
+
+	return false
+}
+
+// HasEvent reports whether span recorded an event named name.
+func HasEvent(span tracetest.SpanStub, name string) bool {
+	for _, event := range span.Events {
+		if event.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+package spectratest_test
+
+import (
+	"testing"
+
+	"github.com/monkescience/spectra/spectratest"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func testSpans() tracetest.SpanStubs {
+	return tracetest.SpanStubs{
+		{
+			Name: "TestCheckout",
+			Attributes: []attribute.KeyValue{
+				attribute.String("test.status", "pass"),
+				attribute.Int("retries", 2),
+			},
+		},
+		{
+			Name:   "TestCheckout/act",
+			Events: []sdktrace.Event{{Name: "log"}},
+		},
+	}
+}
+
+func TestFindSpan(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		span   string
+		wantOK bool
+	}{
+		{name: "found", span: "TestCheckout/act", wantOK: true},
+		{name: "missing", span: "TestCheckout/assert", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			spans := testSpans()
+
+			// when
+			span, ok := spectratest.FindSpan(spans, tt.span)
+
+			// then
+			if ok != tt.wantOK {
+				t.Fatalf("expected found %v, got %v", tt.wantOK, ok)
+			}
+
+			if ok && span.Name != tt.span {
+				t.Errorf("expected span %q, got %q", tt.span, span.Name)
+			}
+		})
+	}
+}
+
+func TestHasAttribute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		key   string
+		value attribute.Value
+		want  bool
+	}{
+		{name: "matching string", key: "test.status", value: attribute.StringValue("pass"), want: true},
+		{name: "matching int", key: "retries", value: attribute.IntValue(2), want: true},
+		{name: "different value", key: "test.status", value: attribute.StringValue("fail"), want: false},
+		{name: "different type", key: "retries", value: attribute.StringValue("2"), want: false},
+		{name: "missing key", key: "test.phase", value: attribute.StringValue("setup"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			span := testSpans()[0]
+
+			// when
+			got := spectratest.HasAttribute(span, tt.key, tt.value)
+
+			// then
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHasEvent(t *testing.T) {
+	t.Parallel()
+
+	// given
+	span := testSpans()[1]
+
+	// when/then
+	if !spectratest.HasEvent(span, "log") {
+		t.Error("expected log event to be found")
+	}
+
+	if spectratest.HasEvent(span, "exception") {
+		t.Error("expected exception event not to be found")
+	}
+}
@@ -1,19 +1,30 @@
 package spectra
 
 import (
+	"context"
+	"slices"
 	"testing"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // Run runs a subtest with its own span as a child of the current test span.
+// A panic in the subtest is recorded on its span before being re-raised.
 // For parallel tests (when t.Parallel() is called), the subtest span is linked
-// to the parent span rather than being a direct child.
+// to the parent span rather than being a direct child; see Parallel.
 func (t *T) Run(name string, f func(*T)) bool {
 	t.Helper()
 
-	tt, ok := t.tb.(*testing.T)
+	return t.run(name, f)
+}
+
+// run runs f as a subtest named name whose span carries attrs.
+func (t *T) run(name string, f func(*T), attrs ...attribute.KeyValue) bool {
+	t.Helper()
+
+	tt, ok := t.TB.(*testing.T)
 	if !ok {
 		t.Fatal("spectra: Run() requires *testing.T, not *testing.B")
 
@@ -23,49 +34,147 @@ func (t *T) Run(name string, f func(*T)) bool {
 	return tt.Run(name, func(innerT *testing.T) {
 		innerT.Helper()
 
-		ctx, span := t.tracer.Start(
-			t.ctx,
-			innerT.Name(),
-			trace.WithAttributes(
-				attribute.String(attrTestName, innerT.Name()),
-				attribute.String(attrTestParent, t.Name()),
-			),
-		)
-
-		st := &T{
-			tb:      innerT,
-			ctx:     ctx,
-			span:    span,
-			tracer:  t.tracer,
-			spectra: t.spectra,
-		}
-
-		innerT.Cleanup(func() {
-			code, message := determineSubtestStatus(innerT)
-			span.SetStatus(code, message)
-
-			span.End()
-		})
+		st := t.newSubtest(innerT, attrs...)
+
+		defer st.RecoverPanic()
 
 		f(st)
 	})
 }
 
+// RunParallel runs f as a parallel subtest, calling Parallel before f so the
+// subtest span is a root linked to the current test span. It is shorthand for
+// calling Parallel at the top of a Run callback.
+//
+// Example:
+//
+//	for _, tc := range cases {
+//	    st.RunParallel(tc.name, func(st *spectra.T) {
+//	        check(st, tc)
+//	    })
+//	}
+func (t *T) RunParallel(name string, f func(*T)) bool {
+	t.Helper()
+
+	return t.Run(name, func(st *T) {
+		st.Parallel()
+
+		f(st)
+	})
+}
+
+// newSubtest starts a child span for innerT and registers a cleanup that ends
+// it with the subtest's status and records its metrics.
+func (t *T) newSubtest(innerT testing.TB, attrs ...attribute.KeyValue) *T {
+	innerT.Helper()
+
+	startTime := time.Now()
+
+	parentCtx := t.Context()
+	spanOptions := []trace.SpanStartOption{
+		trace.WithTimestamp(startTime),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(t.spectra.defaultAttributes()...),
+		trace.WithAttributes(subtestAttributes(innerT.Name(), t.Name())...),
+		trace.WithAttributes(attrs...),
+	}
+
+	//nolint:spancheck // Ended by the cleanup below through st.span, which Parallel may replace.
+	ctx, span := t.tracer.Start(parentCtx, t.spectra.testSpanName(innerT.Name()), spanOptions...)
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	st := &T{
+		TB:          innerT,
+		ctx:         ctx,
+		cancel:      cancel,
+		span:        span,
+		spanParent:  trace.SpanContextFromContext(parentCtx),
+		spanOptions: spanOptions,
+		tracer:      t.tracer,
+		spectra:     t.spectra,
+		parent:      t,
+		startTime:   startTime,
+	}
+
+	recordTestActive(ctx, t.spectra, 1)
+
+	innerT.Cleanup(func() {
+		duration := time.Since(st.startTime)
+
+		span := st.Span()
+
+		code, message, status := determineSubtestStatus(st)
+		span.SetStatus(code, message)
+		span.SetAttributes(attribute.String(attrTestStatus, status))
+
+		span.End()
+
+		recordTestActive(st.Context(), st.spectra, -1)
+		recordTestMetrics(st.Context(), st.spectra, innerT.Name(), duration, status)
+		st.spectra.reportTest(st.result(status, duration))
+
+		cancel()
+	})
+
+	return st
+}
+
 // Parallel marks the test as capable of running in parallel.
-// When parallel is used, the span relationship is preserved via span links
-// rather than parent-child relationships.
+// For subtests, the span started by Run is replaced with a new root span that
+// links to the parent span, so the parent's timing isn't skewed by subtests
+// that run after it has returned. The original span is renamed to
+// "TestName/parallel" with test.phase=parallel and ended, so it covers the
+// subtest up to the Parallel call; span processors see it start and end like
+// any other span. Call Parallel before recording anything on the span, as
+// events recorded earlier stay on the original span.
 func (t *T) Parallel() {
 	t.Helper()
 
-	tt, ok := t.tb.(*testing.T)
+	tt, ok := t.TB.(*testing.T)
 	if !ok {
 		return
 	}
 
-	// Add link to parent span before going parallel.
-	t.span.AddEvent("parallel", trace.WithAttributes(
-		attribute.String("parent.trace_id", t.span.SpanContext().TraceID().String()),
-	))
+	if t.parent != nil {
+		t.detachFromParent()
+	}
 
 	tt.Parallel()
 }
+
+// detachFromParent restarts the subtest span as a new root linked to the
+// parent span, ending the original span as "TestName/parallel".
+func (t *T) detachFromParent() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	startTime := time.Now()
+
+	spanOptions := append(
+		slices.Clone(t.spanOptions),
+		trace.WithTimestamp(startTime),
+		trace.WithNewRoot(),
+		trace.WithLinks(trace.Link{SpanContext: t.parent.Span().SpanContext()}),
+	)
+
+	//nolint:spancheck // The span is ended by the subtest cleanup registered in Run.
+	ctx, span := t.tracer.Start(t.ctx, t.spectra.testSpanName(t.Name()), spanOptions...)
+
+	t.span.SetName(t.spectra.spanName(t.Name() + spanParallel))
+	t.span.SetAttributes(attribute.String(attrTestPhase, "parallel"))
+	t.span.End(trace.WithTimestamp(startTime))
+
+	t.ctx = ctx
+	t.span = span
+	t.spanParent = trace.SpanContext{}
+	t.spanOptions = spanOptions
+	t.startTime = startTime
+}
+
+func subtestAttributes(name, parent string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String(attrTestName, name),
+		attribute.String(attrTestParent, parent),
+	}
+}
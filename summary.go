@@ -0,0 +1,55 @@
+package spectra
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// summaryEntry is one test in the JSON summary.
+type summaryEntry struct {
+	Name            string  `json:"name"`
+	Status          string  `json:"status"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	TraceID         string  `json:"trace_id"`
+}
+
+// jsonSummary collects test results and writes them as a JSON array. Like
+// the JUnit report it is fed every completed test and subtest, independent of
+// sampling and export.
+type jsonSummary struct {
+	w io.Writer
+
+	mu      sync.Mutex
+	entries []summaryEntry
+}
+
+func newJSONSummary(w io.Writer) *jsonSummary {
+	return &jsonSummary{w: w, entries: []summaryEntry{}}
+}
+
+func (s *jsonSummary) record(result TestResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, summaryEntry{
+		Name:            result.Name,
+		Status:          result.Status,
+		DurationSeconds: result.Duration.Seconds(),
+		TraceID:         result.TraceID,
+	})
+}
+
+// write writes the recorded tests to w as a JSON array.
+func (s *jsonSummary) write() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := json.NewEncoder(s.w).Encode(s.entries)
+	if err != nil {
+		return fmt.Errorf("write json summary: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package spectra
+
+import (
+	"maps"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RunTable runs f for each case in cases as a subtest of t named by its key,
+// in sorted key order so runs are deterministic. Each subtest span carries the
+// case name in test.case and its position in that order in test.case_index.
+// RunTable reports whether every case passed. It is a function rather than a
+// method of T because Go methods cannot have type parameters.
+//
+// Example:
+//
+//	spectra.RunTable(st, map[string]string{
+//	    "empty":  "",
+//	    "simple": "a,b",
+//	}, func(st *spectra.T, input string) {
+//	    parse(st.Context(), input)
+//	})
+func RunTable[C any](t *T, cases map[string]C, f func(*T, C)) bool {
+	t.Helper()
+
+	ok := true
+
+	for i, name := range slices.Sorted(maps.Keys(cases)) {
+		tc := cases[name]
+
+		passed := t.run(name, func(st *T) {
+			f(st, tc)
+		}, attribute.String(attrTestCase, name), attribute.Int(attrTestCaseIndex, i))
+
+		ok = ok && passed
+	}
+
+	return ok
+}
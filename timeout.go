@@ -0,0 +1,54 @@
+package spectra
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTimeout fails the test if it is still running after d, independent of
+// go test -timeout. On expiry a "timeout" event is recorded, the span status
+// is set to Error and the test is marked failed with Error; the test itself
+// keeps running. The timer is stopped when the test completes.
+//
+// Example:
+//
+//	st.WithTimeout(5 * time.Second)
+//	waitForReplication(st.Context())
+func (t *T) WithTimeout(d time.Duration) {
+	t.Helper()
+
+	var (
+		mu      sync.Mutex
+		stopped bool
+	)
+
+	timer := time.AfterFunc(d, func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if stopped {
+			return
+		}
+
+		// Read the span under t.mu, as Parallel and ForceSample may replace it.
+		span := t.Span()
+		span.AddEvent(timeoutEventName, trace.WithAttributes(
+			attribute.String(attrTestTimeout, d.String()),
+		))
+		span.SetStatus(codes.Error, "test timed out")
+		t.Errorf("spectra: test exceeded timeout of %v", d)
+	})
+
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		stopped = true
+
+		timer.Stop()
+	})
+}